@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Placeholders expanded in -output file names, so each run of a batch
+// writes its own file.
+const (
+	placeholderIndex = "{i}"
+	placeholderImag  = "{imag}"
+	placeholderReal  = "{real}"
+)
+
+// outputPath expands the placeholders in template for the i-th run at s and
+// joins the result onto dir (if set).
+func outputPath(dir, template string, i int, s complex128) string {
+	name := strings.NewReplacer(
+		placeholderIndex, strconv.Itoa(i),
+		placeholderImag, strconv.FormatFloat(imag(s), 'f', -1, 64),
+		placeholderReal, strconv.FormatFloat(real(s), 'f', -1, 64),
+	).Replace(template)
+	if dir == "" {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
+
+// batchTemplate returns template unchanged if it names each run distinctly,
+// and otherwise inserts _{i} before its extension so runs don't overwrite
+// each other.
+func batchTemplate(template string) string {
+	for _, p := range []string{placeholderIndex, placeholderImag, placeholderReal} {
+		if strings.Contains(template, p) {
+			return template
+		}
+	}
+	ext := filepath.Ext(template)
+	return strings.TrimSuffix(template, ext) + "_" + placeholderIndex + ext
+}
+
+// parseBatch parses a comma-separated list of imaginary parts.
+func parseBatch(list string) ([]float64, error) {
+	var ts []float64
+	for _, field := range strings.Split(list, ",") {
+		t, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -batch value %q: %v", field, err)
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
+
+// renderBatch renders the ζ spiral at s = 0.5 + it for each t in ts, saving
+// each to the file template names in dir, which is created if missing.
+// aggressiveness > 0 downsamples each chain first, as downsampling
+// selects, and chunks controls how each sum is split. It returns the files
+// written.
+func renderBatch(ts []float64, dir, template string, aggressiveness float64, downsampling DownsampleOptions, cfg RenderConfig, chunks ChunkConfig) ([]string, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	template = batchTemplate(template)
+
+	files := make([]string, 0, len(ts))
+	for i, t := range ts {
+		s := complex(0.5, t)
+		spiral := calculateSpiralPartialSums(s, chunks)
+		links := spiral.Links
+		switch {
+		case aggressiveness > 0 && spiral.Links32 != nil:
+			links = downsampleComplex(spiral.Links32, cfg.OutputSize, aggressiveness, downsampling, false)
+		case spiral.Links32 != nil:
+			links = widenLinks(spiral.Links32)
+		case aggressiveness > 0:
+			links = downsampleComplex(links, cfg.OutputSize, aggressiveness, downsampling, false)
+		}
+
+		cfg.ResidualTarget = spiral.Total
+		if cfg.Reference {
+			cfg.ReferencePoint = &spiral.Total
+		}
+		filename := outputPath(dir, template, i, s)
+		img := renderLinks(seedLinks(links, chunks.Seed), cfg)
+		if err := savePNG(img, filename); err != nil {
+			return files, err
+		}
+		log.Printf("Batch %d/%d: s = %v, ζ(s) ≈ %v, saved as %s", i+1, len(ts), s, spiral.Total, filename)
+		files = append(files, filename)
+	}
+	return files, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test that a batch of three inputs writes three distinctly named images
+// into a directory it creates.
+func TestRenderBatch_WritesTemplatedFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "frames")
+	ts := []float64{100, 200.5, 300}
+	cfg := RenderConfig{OutputSize: 64, Renderer: RendererSimple}
+
+	files, err := renderBatch(ts, dir, "zeta_{i}_{real}_{imag}.png", 0, DownsampleOptions{}, cfg, ChunkConfig{})
+	if err != nil {
+		t.Fatalf("renderBatch: %v", err)
+	}
+
+	want := []string{"zeta_0_0.5_100.png", "zeta_1_0.5_200.5.png", "zeta_2_0.5_300.png"}
+	if len(files) != len(want) {
+		t.Fatalf("got %d files, want %d", len(files), len(want))
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != len(want) {
+		t.Errorf("got %d entries in the directory, want %d", len(entries), len(want))
+	}
+	for i, name := range want {
+		if files[i] != filepath.Join(dir, name) {
+			t.Errorf("file %d: got %s, want %s", i, files[i], filepath.Join(dir, name))
+		}
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Errorf("%s: missing or empty (stat error %v)", name, err)
+		}
+	}
+}
+
+// Test that a template without placeholders gets the run index, so batch
+// runs never overwrite each other.
+func TestBatchTemplate(t *testing.T) {
+	for template, want := range map[string]string{
+		"spiral.png":        "spiral_{i}.png",
+		"spiral_{imag}.png": "spiral_{imag}.png",
+		"frame":             "frame_{i}",
+	} {
+		if got := batchTemplate(template); got != want {
+			t.Errorf("batchTemplate(%q) = %q, want %q", template, got, want)
+		}
+	}
+}
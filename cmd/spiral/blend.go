@@ -0,0 +1,19 @@
+package main
+
+// Blend modes accepted by RenderConfig.Blend.
+const (
+	// BlendAdditive adds every stroke onto the background, so paths
+	// that overlap, even within one worker's share, brighten towards
+	// white. It suits dense spirals.
+	BlendAdditive = "additive"
+	// BlendOver stacks every stroke with Porter-Duff over, so
+	// overlapping paths only approach the stroke color instead of
+	// summing past it. It suits sparse ones.
+	BlendOver = "over"
+)
+
+// validBlend reports whether name is a known blend mode. The empty string
+// selects the default additive blending.
+func validBlend(name string) bool {
+	return name == "" || name == BlendAdditive || name == BlendOver
+}
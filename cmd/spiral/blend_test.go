@@ -0,0 +1,37 @@
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+// Test that where a path crosses itself within one worker's share, the
+// additive blend lights the crossing brighter than over.
+func TestRenderLinks_AdditiveBrighterAtCrossing(t *testing.T) {
+	defer func(n int) { renderWorkers = n }(renderWorkers)
+	renderWorkers = 1
+
+	const size = 64
+	// An X: up one diagonal, along the top and back down the other, so the
+	// diagonals cross in the middle.
+	links := []complex128{0, complex(1, 1), complex(0, 1), 1}
+	// The brightest pixel around the crossing, since thin strokes needn't
+	// meet on one pixel.
+	crossing := func(img *image.RGBA) uint8 {
+		var peak uint8
+		for y := size/2 - 2; y <= size/2+2; y++ {
+			for x := size/2 - 2; x <= size/2+2; x++ {
+				peak = max(peak, img.RGBAAt(x, y).R)
+			}
+		}
+		return peak
+	}
+	for _, renderer := range []string{RendererDraw2D, RendererSimple} {
+		additive := crossing(renderLinks(links, RenderConfig{OutputSize: size, Renderer: renderer, Blend: BlendAdditive}))
+		over := crossing(renderLinks(links, RenderConfig{OutputSize: size, Renderer: renderer, Blend: BlendOver}))
+		t.Logf("%s: crossing brightness %d additive, %d over", renderer, additive, over)
+		if additive <= over {
+			t.Errorf("%s: got crossing brightness %d additive, %d over; want additive brighter", renderer, additive, over)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// frameFunc runs one compute+downsample+render pass with the sum capped at
+// n terms and reports how long it took.
+type frameFunc func(n int, aggressiveness float64) time.Duration
+
+// maxBudgetIterations bounds how many trial frames fitFrameBudget runs.
+const maxBudgetIterations = 8
+
+// fitFrameBudget searches for a term count (at most fullN) and downsampling
+// aggressiveness whose frame fits in budget. Each trial that runs over scales
+// n down by the overrun, assuming cost is roughly linear in n, and raises the
+// aggressiveness one step. It returns the last settings tried and their time.
+func fitFrameBudget(fullN int, budget time.Duration, frame frameFunc) (n int, aggressiveness float64, elapsed time.Duration) {
+	n = fullN
+	for i := 0; i < maxBudgetIterations; i++ {
+		elapsed = frame(n, aggressiveness)
+		if elapsed <= budget {
+			return n, aggressiveness, elapsed
+		}
+		if n <= MinN && aggressiveness >= MaxAggressiveness {
+			break
+		}
+
+		// Aim slightly under the budget so the next trial is likely to fit.
+		scale := 0.9 * float64(budget) / float64(elapsed)
+		n = int(math.Max(float64(MinN), float64(n)*scale))
+		aggressiveness = math.Min(aggressiveness+1, MaxAggressiveness)
+	}
+	return n, aggressiveness, elapsed
+}
+
+// measureFrame returns a frameFunc timing the real pipeline for the series
+// with terms termFn and tail correction tail, summed with chunks and
+// downsampled as downsampling selects. Trial frames sum exactly n terms and
+// don't write chunks.Dump.
+func measureFrame(termFn func(k int) complex128, tail func(N int) complex128, downsampling DownsampleOptions, cfg RenderConfig, chunks ChunkConfig) frameFunc {
+	chunks.Dump = nil
+	return func(n int, aggressiveness float64) time.Duration {
+		start := time.Now()
+
+		spiral := seriesPartialSums(n, termFn, tail, chunks)
+
+		links := spiral.Links
+		switch {
+		case aggressiveness > 0 && spiral.Links32 != nil:
+			links = downsampleComplex(spiral.Links32, cfg.OutputSize, aggressiveness, downsampling, false)
+		case spiral.Links32 != nil:
+			links = widenLinks(spiral.Links32)
+		case aggressiveness > 0:
+			links = downsampleComplex(links, cfg.OutputSize, aggressiveness, downsampling, false)
+		}
+		renderLinks(seedLinks(links, chunks.Seed), cfg)
+
+		return time.Since(start)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test that a tighter frame budget settles on fewer terms.
+func TestFitFrameBudget(t *testing.T) {
+	// Synthetic cost: 1µs per term, a little cheaper with more downsampling.
+	frame := func(n int, aggressiveness float64) time.Duration {
+		return time.Duration(float64(n)*(1-aggressiveness/10)) * time.Microsecond
+	}
+	fullN := 1_000_000
+
+	tightN, tightAgg, tightElapsed := fitFrameBudget(fullN, 10*time.Millisecond, frame)
+	looseN, looseAgg, looseElapsed := fitFrameBudget(fullN, 2*time.Second, frame)
+
+	if tightN >= looseN {
+		t.Errorf("tight budget chose N=%d, want fewer than generous budget's N=%d", tightN, looseN)
+	}
+	if looseN != fullN || looseAgg != 0 {
+		t.Errorf("generous budget should keep full fidelity, got N=%d aggressiveness=%.1f", looseN, looseAgg)
+	}
+	if tightElapsed > 10*time.Millisecond {
+		t.Errorf("tight budget not met: %v (N=%d, aggressiveness=%.1f)", tightElapsed, tightN, tightAgg)
+	}
+	if looseElapsed > 2*time.Second {
+		t.Errorf("generous budget not met: %v", looseElapsed)
+	}
+}
+
+// Test that trial frames take their term count as an argument instead of
+// overriding MaxN, so ζ evaluated alongside them keeps its own N.
+func TestMeasureFrame_LeavesMaxNAlone(t *testing.T) {
+	s := complex(0.5, 5000)
+	want := Zeta(s)
+	frame := measureFrame(zetaTerm(s), zetaTail(s), DownsampleOptions{}, RenderConfig{OutputSize: 64, Renderer: RendererSimple}, ChunkConfig{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			frame(MinN, 0)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if got := Zeta(s); got != want {
+			t.Fatalf("got ζ(s) = %v during a trial frame, want %v", got, want)
+		}
+	}
+	<-done
+}
+
+// Test that trial frames sum the series they are given, with terms k < n
+// and the tail from n on, rather than ζ.
+func TestMeasureFrame_UsesGivenSeries(t *testing.T) {
+	s := complex(0.5, 5000)
+	var terms, maxK atomic.Int64
+	termFn := func(k int) complex128 {
+		terms.Add(1)
+		for {
+			old := maxK.Load()
+			if int64(k) <= old || maxK.CompareAndSwap(old, int64(k)) {
+				break
+			}
+		}
+		return zetaTerm(s)(k)
+	}
+	frame := measureFrame(termFn, zetaTail(s), DownsampleOptions{}, RenderConfig{OutputSize: 64, Renderer: RendererSimple}, ChunkConfig{})
+
+	n := 3 * MinN
+	frame(n, 0)
+	if got := terms.Load(); got != int64(n-1) {
+		t.Errorf("got %d term evaluations, want n-1 = %d", got, n-1)
+	}
+	if got := maxK.Load(); got != int64(n-1) {
+		t.Errorf("got largest k %d, want n-1 = %d", got, n-1)
+	}
+}
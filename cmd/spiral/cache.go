@@ -0,0 +1,110 @@
+package main
+
+import (
+	"container/list"
+	"math"
+	"sync"
+)
+
+const (
+	// defaultZetaCacheSize is how many results CachedZeta keeps.
+	defaultZetaCacheSize = 256
+	// zetaCacheQuantum is the grid s is rounded to before lookup, so requests
+	// differing only by float noise share one entry.
+	zetaCacheQuantum = 1e-9
+)
+
+// zetaCache is a thread-safe LRU of ζ values keyed by quantized s.
+type zetaCache struct {
+	mu       sync.Mutex
+	capacity int
+	quantum  float64
+	order    *list.List // front is most recently used
+	entries  map[complex128]*list.Element
+	eval     func(complex128) complex128
+}
+
+type zetaCacheEntry struct {
+	key   complex128
+	value complex128
+}
+
+// newZetaCache returns an empty cache holding up to capacity results of eval.
+func newZetaCache(capacity int, quantum float64, eval func(complex128) complex128) *zetaCache {
+	return &zetaCache{
+		capacity: max(capacity, 1),
+		quantum:  quantum,
+		order:    list.New(),
+		entries:  make(map[complex128]*list.Element),
+		eval:     eval,
+	}
+}
+
+// key rounds s onto the cache's quantization grid.
+func (c *zetaCache) key(s complex128) complex128 {
+	return complex(
+		math.Round(real(s)/c.quantum)*c.quantum,
+		math.Round(imag(s)/c.quantum)*c.quantum,
+	)
+}
+
+// Get returns the cached value for s, evaluating and storing it on a miss.
+// The evaluation runs without the lock held, so concurrent misses on the
+// same key may both compute; the results are identical and one is kept.
+func (c *zetaCache) Get(s complex128) complex128 {
+	k := c.key(s)
+
+	c.mu.Lock()
+	if el, ok := c.entries[k]; ok {
+		c.order.MoveToFront(el)
+		v := el.Value.(*zetaCacheEntry).value
+		c.mu.Unlock()
+		return v
+	}
+	c.mu.Unlock()
+
+	v := c.eval(k)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[k]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*zetaCacheEntry).value
+	}
+	c.entries[k] = c.order.PushFront(&zetaCacheEntry{key: k, value: v})
+	c.evict()
+	return v
+}
+
+// Resize changes the capacity, evicting least recently used entries if the
+// cache is now over it.
+func (c *zetaCache) Resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity = max(capacity, 1)
+	c.evict()
+}
+
+// evict drops least recently used entries until the cache fits. c.mu must
+// be held.
+func (c *zetaCache) evict() {
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*zetaCacheEntry).key)
+	}
+}
+
+var defaultZetaCache = newZetaCache(defaultZetaCacheSize, zetaCacheQuantum, Zeta)
+
+// CachedZeta is Zeta memoized in a shared LRU cache, for callers such as
+// interactive explorers that re-request the same s. s is quantized to
+// zetaCacheQuantum, so the result is ζ at the nearest grid point.
+func CachedZeta(s complex128) complex128 {
+	return defaultZetaCache.Get(s)
+}
+
+// SetZetaCacheSize sets how many results CachedZeta keeps.
+func SetZetaCacheSize(n int) {
+	defaultZetaCache.Resize(n)
+}
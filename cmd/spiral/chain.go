@@ -0,0 +1,159 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Chain is an ordered sequence of partial-sum links tracing the spiral.
+type Chain []complex128
+
+// Bounds is an axis-aligned rectangle in data coordinates.
+type Bounds struct {
+	MinX, MaxX, MinY, MaxY float64
+}
+
+// Union returns the smallest Bounds covering both b and o.
+func (b Bounds) Union(o Bounds) Bounds {
+	return Bounds{
+		MinX: math.Min(b.MinX, o.MinX),
+		MaxX: math.Max(b.MaxX, o.MaxX),
+		MinY: math.Min(b.MinY, o.MinY),
+		MaxY: math.Max(b.MaxY, o.MaxY),
+	}
+}
+
+// Contains reports whether p lies in b, edges included.
+func (b Bounds) Contains(p complex128) bool {
+	return real(p) >= b.MinX && real(p) <= b.MaxX && imag(p) >= b.MinY && imag(p) <= b.MaxY
+}
+
+// Width returns b's extent along the real axis.
+func (b Bounds) Width() float64 { return b.MaxX - b.MinX }
+
+// Height returns b's extent along the imaginary axis.
+func (b Bounds) Height() float64 { return b.MaxY - b.MinY }
+
+// Center returns the point midway between b's corners.
+func (b Bounds) Center() complex128 {
+	return complex((b.MinX+b.MaxX)/2, (b.MinY+b.MaxY)/2)
+}
+
+// boundsOf returns computeBounds of links as a Bounds. links must not be
+// empty.
+func boundsOf[T linkValue](links []T) Bounds {
+	minX, maxX, minY, maxY := computeBounds(links)
+	return Bounds{minX, maxX, minY, maxY}
+}
+
+// computeBounds returns the extent of links along the real (X) and imaginary
+// (Y) axes. links must not be empty. NaN coordinates never widen the bounds;
+// an axis that is NaN in every link has NaN bounds.
+func computeBounds[T linkValue](links []T) (minX, maxX, minY, maxY float64) {
+	minX, maxX = math.Inf(1), math.Inf(-1)
+	minY, maxY = math.Inf(1), math.Inf(-1)
+	for _, link := range links {
+		x := real(complex128(link))
+		y := imag(complex128(link))
+		if x < minX {
+			minX = x
+		}
+		if x > maxX {
+			maxX = x
+		}
+		if y < minY {
+			minY = y
+		}
+		if y > maxY {
+			maxY = y
+		}
+	}
+	// Only an axis with no comparable coordinate keeps its inverted seeds.
+	if minX > maxX {
+		minX, maxX = math.NaN(), math.NaN()
+	}
+	if minY > maxY {
+		minY, maxY = math.NaN(), math.NaN()
+	}
+	return minX, maxX, minY, maxY
+}
+
+// orderedKey maps f to an int64 that sorts the way f does, so bounds can be
+// tracked with integer min and max, which compile to conditional moves
+// rather than branches. -0 sorts just below +0.
+func orderedKey(f float64) int64 {
+	b := int64(math.Float64bits(f))
+	return b ^ (b >> 63 & math.MaxInt64)
+}
+
+// fromOrderedKey inverts orderedKey.
+func fromOrderedKey(k int64) float64 {
+	return math.Float64frombits(uint64(k ^ (k >> 63 & math.MaxInt64)))
+}
+
+// computeBoundsBranchless returns the same bounds as computeBounds without a
+// data-dependent branch per comparison. NaN keys sort beyond the infinities,
+// so any NaN surfaces as an out-of-range extreme; that rare case falls back
+// to computeBounds for its NaN policy rather than slowing the common loop.
+// BenchmarkComputeBounds decides which one callers use: a spiral's extremes
+// change rarely, so computeBounds' branches predict well and it stays the
+// default until the branchless loop measures faster.
+func computeBoundsBranchless[T linkValue](links []T) (minX, maxX, minY, maxY float64) {
+	loX, hiX := int64(math.MaxInt64), int64(math.MinInt64)
+	loY, hiY := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, link := range links {
+		kx := orderedKey(real(complex128(link)))
+		ky := orderedKey(imag(complex128(link)))
+		loX, hiX = min(loX, kx), max(hiX, kx)
+		loY, hiY = min(loY, ky), max(hiY, ky)
+	}
+	lo, hi := orderedKey(math.Inf(-1)), orderedKey(math.Inf(1))
+	if loX < lo || hiX > hi || loY < lo || hiY > hi {
+		return computeBounds(links)
+	}
+	return fromOrderedKey(loX), fromOrderedKey(hiX), fromOrderedKey(loY), fromOrderedKey(hiY)
+}
+
+// AspectRatio returns the width of the chain's bounding box divided by its
+// height. A chain with no vertical extent is +Inf wide, and a single point
+// (or an empty chain) is treated as square.
+func (c Chain) AspectRatio() float64 {
+	if len(c) == 0 {
+		return 1
+	}
+	b := boundsOf(c)
+	width, height := b.Width(), b.Height()
+	switch {
+	case height > 0:
+		return width / height
+	case width > 0:
+		return math.Inf(1)
+	default:
+		return 1
+	}
+}
+
+// BoundingArea returns the area of the chain's axis-aligned bounding box.
+func (c Chain) BoundingArea() float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	b := boundsOf(c)
+	return b.Width() * b.Height()
+}
+
+// mirrorLinks returns the complex conjugates of links, the chain reflected
+// across the real axis.
+func mirrorLinks(links []complex128) []complex128 {
+	mirrored := make([]complex128, len(links))
+	for i, link := range links {
+		mirrored[i] = cmplx.Conj(link)
+	}
+	return mirrored
+}
+
+// mirrorView widens b vertically to be symmetric about the real axis, so it
+// frames a chain and its mirror.
+func mirrorView(b Bounds) Bounds {
+	return b.Union(Bounds{MinX: b.MinX, MaxX: b.MaxX, MinY: -b.MaxY, MaxY: -b.MinY})
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// BenchmarkComputeBounds compares the branchy and branchless bounds loops
+// on a spiral and on its float32 links.
+func BenchmarkComputeBounds(b *testing.B) {
+	for _, n := range []int{100_000, 1_000_000} {
+		links := testutil.SpiralLinks(n)
+		links32 := make([]complex64, n)
+		for i, link := range links {
+			links32[i] = complex64(link)
+		}
+		run := func(name string, bounds func() (float64, float64, float64, float64)) {
+			b.Run(fmt.Sprintf("%s_%d", name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					// Prevent compiler optimization
+					if minX, _, _, _ := bounds(); minX > 0 {
+						b.Fatalf("got minX %v, want the spiral to cross zero", minX)
+					}
+				}
+				b.ReportMetric(float64(n*b.N)/b.Elapsed().Seconds(), "links/s")
+			})
+		}
+		run("branchy", func() (float64, float64, float64, float64) { return computeBounds(links) })
+		run("branchless", func() (float64, float64, float64, float64) { return computeBoundsBranchless(links) })
+		run("branchy32", func() (float64, float64, float64, float64) { return computeBounds(links32) })
+		run("branchless32", func() (float64, float64, float64, float64) { return computeBoundsBranchless(links32) })
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Test that the branchless bounds match computeBounds on random chains,
+// including ones seeded with NaNs, infinities and signed zeros.
+func TestComputeBoundsBranchless_MatchesBranchy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	specials := []float64{math.NaN(), math.Inf(1), math.Inf(-1), 0, math.Copysign(0, -1)}
+	for trial := 0; trial < 200; trial++ {
+		links := make([]complex128, 1+rng.Intn(500))
+		for i := range links {
+			links[i] = complex(rng.NormFloat64()*1e3, rng.NormFloat64()*1e-3)
+		}
+		// Later trials replace a few coordinates with special values.
+		for j := 0; j < trial%6; j++ {
+			i := rng.Intn(len(links))
+			v := specials[rng.Intn(len(specials))]
+			if rng.Intn(2) == 0 {
+				links[i] = complex(v, imag(links[i]))
+			} else {
+				links[i] = complex(real(links[i]), v)
+			}
+		}
+
+		got := [4]float64{}
+		got[0], got[1], got[2], got[3] = computeBoundsBranchless(links)
+		want := [4]float64{}
+		want[0], want[1], want[2], want[3] = computeBounds(links)
+		for k := range got {
+			if got[k] != want[k] && !(math.IsNaN(got[k]) && math.IsNaN(want[k])) {
+				t.Fatalf("trial %d: got bounds %v, want %v", trial, got, want)
+			}
+		}
+	}
+}
+
+// Test the NaN policy: NaN coordinates don't widen the bounds, even in the
+// first link, and an axis that is NaN throughout has NaN bounds.
+func TestComputeBounds_NaN(t *testing.T) {
+	nan := math.NaN()
+	links := []complex128{complex(nan, 1), complex(2, nan), complex(-3, 4), complex(nan, nan)}
+	for name, bounds := range map[string]func([]complex128) (float64, float64, float64, float64){
+		"branchy":    computeBounds[complex128],
+		"branchless": computeBoundsBranchless[complex128],
+	} {
+		minX, maxX, minY, maxY := bounds(links)
+		if minX != -3 || maxX != 2 || minY != 1 || maxY != 4 {
+			t.Errorf("%s: got X [%v, %v] Y [%v, %v], want X [-3, 2] Y [1, 4]", name, minX, maxX, minY, maxY)
+		}
+
+		minX, maxX, minY, maxY = bounds([]complex128{complex(nan, 1), complex(nan, 2)})
+		if !math.IsNaN(minX) || !math.IsNaN(maxX) || minY != 1 || maxY != 2 {
+			t.Errorf("%s: got X [%v, %v] Y [%v, %v] for an all-NaN X axis, want X [NaN, NaN] Y [1, 2]",
+				name, minX, maxX, minY, maxY)
+		}
+	}
+}
+
+// Test that the union of two disjoint boxes spans both and the gap between
+// them, whichever order they are combined in.
+func TestBounds_UnionDisjoint(t *testing.T) {
+	a := Bounds{MinX: -3, MaxX: -1, MinY: 2, MaxY: 4}
+	b := Bounds{MinX: 1, MaxX: 5, MinY: -6, MaxY: -2}
+	want := Bounds{MinX: -3, MaxX: 5, MinY: -6, MaxY: 4}
+	if got := a.Union(b); got != want {
+		t.Errorf("a.Union(b) = %+v, want %+v", got, want)
+	}
+	if got := b.Union(a); got != want {
+		t.Errorf("b.Union(a) = %+v, want %+v", got, want)
+	}
+	if got := want.Width(); got != 8 {
+		t.Errorf("Width() = %v, want 8", got)
+	}
+	if got := want.Height(); got != 10 {
+		t.Errorf("Height() = %v, want 10", got)
+	}
+	if got := want.Center(); got != complex(1, -1) {
+		t.Errorf("Center() = %v, want (1-1i)", got)
+	}
+	// The gap between the boxes is covered by the union but neither box.
+	if gap := complex(0, 0); a.Contains(gap) || b.Contains(gap) || !want.Contains(gap) {
+		t.Errorf("the gap at %v should lie only in the union", gap)
+	}
+}
+
+// Test that Contains includes points on every edge and corner and excludes
+// points just beyond them.
+func TestBounds_ContainsEdges(t *testing.T) {
+	b := Bounds{MinX: -1, MaxX: 2, MinY: 0, MaxY: 3}
+	for _, p := range []complex128{
+		complex(-1, 1), complex(2, 1), complex(0, 0), complex(0, 3),
+		complex(-1, 0), complex(2, 3), complex(-1, 3), complex(2, 0),
+	} {
+		if !b.Contains(p) {
+			t.Errorf("Contains(%v) = false for a point on the edge", p)
+		}
+	}
+	for _, p := range []complex128{
+		complex(math.Nextafter(-1, -2), 1), complex(math.Nextafter(2, 3), 1),
+		complex(0, math.Nextafter(0, -1)), complex(0, math.Nextafter(3, 4)),
+		complex(math.NaN(), 1),
+	} {
+		if b.Contains(p) {
+			t.Errorf("Contains(%v) = true for a point outside", p)
+		}
+	}
+}
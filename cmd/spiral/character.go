@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math/cmplx"
+	"sort"
+)
+
+// DirichletCharacter is a periodic, completely multiplicative function χ on
+// the integers, used to weight the terms of an L-series Σ χ(k) k^{-s}.
+type DirichletCharacter struct {
+	// Values[k mod len(Values)] is χ(k); the modulus is len(Values).
+	Values []complex128
+}
+
+// At returns χ(k).
+func (c DirichletCharacter) At(k int) complex128 {
+	return c.Values[k%len(c.Values)]
+}
+
+// Characters are the built-in non-principal characters, keyed by the name
+// accepted by -character.
+var Characters = map[string]DirichletCharacter{
+	// The real character mod 3.
+	"mod3": {Values: []complex128{0, 1, -1}},
+	// The real character mod 4, whose L-function is the Dirichlet beta
+	// function β(s) = 1 - 3^{-s} + 5^{-s} - ..., with β(1) = π/4.
+	"mod4": {Values: []complex128{0, 1, 0, -1}},
+	// The complex character mod 5 sending the generator 2 to i.
+	"mod5": {Values: []complex128{0, 1, 1i, -1i, -1}},
+}
+
+// characterNames returns the names of the built-in characters in order.
+func characterNames() []string {
+	names := make([]string, 0, len(Characters))
+	for name := range Characters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// characterTerm returns the term function χ(k)·k^{-s} of the L(s, χ) series.
+func characterTerm(chi DirichletCharacter, s complex128) func(k int) complex128 {
+	return func(k int) complex128 {
+		c := chi.At(k)
+		if c == 0 {
+			return 0
+		}
+		return c * cmplx.Pow(complex(float64(k), 0), -s)
+	}
+}
+
+// characterTail returns a tail estimate for the L(s, χ) series of a
+// non-principal character. Its values sum to zero over a period, so the
+// partial sums oscillate around the limit; averaging the q partial sums
+// ending in [N, N+q) cancels the oscillation to O(N^{-1-Re(s)}). The tail
+// is the offset of that average from the partial sum before N.
+func characterTail(chi DirichletCharacter, s complex128) func(N int) complex128 {
+	termFn := characterTerm(chi, s)
+	q := len(chi.Values)
+	return func(N int) complex128 {
+		var tail complex128
+		for k := N; k < N+q-1; k++ {
+			tail += complex(float64(N+q-1-k), 0) * termFn(k)
+		}
+		return tail / complex(float64(q), 0)
+	}
+}
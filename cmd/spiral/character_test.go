@@ -0,0 +1,33 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// Test that the mod-4 L-series at s = 1 converges to β(1) = π/4.
+func TestCharacterSeries_DirichletBeta(t *testing.T) {
+	s := complex(1, 0)
+	chi := Characters["mod4"]
+	result := calculateSeriesPartialSums(s, characterTerm(chi, s), characterTail(chi, s), ChunkConfig{})
+
+	// The plain partial sum is only good to about 1/(2N); the tail
+	// brings N = 100 terms to within about 1/(2N²).
+	want := complex(math.Pi/4, 0)
+	if !cmplxEquals(result.Total, want, 1e-4) {
+		t.Errorf("got L(1, χ4) ≈ %v, want π/4 = %v", result.Total, want)
+	}
+}
+
+// Test that every built-in character is completely multiplicative.
+func TestCharacters_Multiplicative(t *testing.T) {
+	for name, chi := range Characters {
+		for m := 1; m < 20; m++ {
+			for n := 1; n < 20; n++ {
+				if got, want := chi.At(m*n), chi.At(m)*chi.At(n); got != want {
+					t.Errorf("%s: χ(%d·%d) = %v, want χ(%d)χ(%d) = %v", name, m, n, got, m, n, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"zeta-scale-go/pkg/compression"
+)
+
+// ExportCSV writes the links as index,real,imag rows with a header line.
+// Values use the shortest representation that round-trips exactly.
+func ExportCSV(links []complex128, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"index", "real", "imag"}); err != nil {
+		return err
+	}
+	for i, link := range links {
+		row := []string{
+			strconv.Itoa(i),
+			strconv.FormatFloat(real(link), 'g', -1, 64),
+			strconv.FormatFloat(imag(link), 'g', -1, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportCSV reads index,real,imag rows into links, one record at a time so
+// large files never need to be held in memory as text. Rows that don't parse
+// are skipped and counted; an optional header row is ignored. Links are kept
+// in file order and the index column is only validated, not used to reorder.
+func ImportCSV(r io.Reader) (links []complex128, skipped int, err error) {
+	cr := csv.NewReader(bufio.NewReader(r))
+	cr.FieldsPerRecord = -1 // count bad rows ourselves rather than aborting
+	cr.ReuseRecord = true
+
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				skipped++
+				continue
+			}
+			return nil, skipped, err
+		}
+
+		if row == 1 && len(record) > 0 && record[0] == "index" {
+			continue
+		}
+
+		link, ok := parseCSVLink(record)
+		if !ok {
+			skipped++
+			continue
+		}
+		links = append(links, link)
+	}
+	return links, skipped, nil
+}
+
+// parseCSVLink parses a single index,real,imag record.
+func parseCSVLink(record []string) (complex128, bool) {
+	if len(record) != 3 {
+		return 0, false
+	}
+	if _, err := strconv.Atoi(record[0]); err != nil {
+		return 0, false
+	}
+	re, err := strconv.ParseFloat(record[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	im, err := strconv.ParseFloat(record[2], 64)
+	if err != nil {
+		return 0, false
+	}
+	return complex(re, im), true
+}
+
+// saveLinksCSV exports the links to a CSV file, atomically so a failed
+// save never leaves a truncated file for -input-csv to load.
+func saveLinksCSV(links []complex128, filename string) error {
+	return compression.WriteFileAtomic(filename, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		if err := ExportCSV(links, bw); err != nil {
+			return err
+		}
+		return bw.Flush()
+	})
+}
+
+// loadLinksCSV imports links from a CSV file, warning about skipped rows.
+func loadLinksCSV(filename string) ([]complex128, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	links, skipped, err := ImportCSV(file)
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		log.Printf("Warning: skipped %d malformed rows in %s", skipped, filename)
+	}
+	if len(links) == 0 {
+		return nil, fmt.Errorf("no valid rows in %s", filename)
+	}
+	log.Printf("Loaded %d links from %s", len(links), filename)
+	return links, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that a spiral exported to CSV re-imports and renders identically.
+func TestCSVRoundTrip(t *testing.T) {
+	links := testutil.SpiralLinks(500)
+
+	var buf bytes.Buffer
+	if err := ExportCSV(links, &buf); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	got, skipped, err := ImportCSV(&buf)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if skipped != 0 {
+		t.Errorf("got %d skipped rows, want 0", skipped)
+	}
+	if len(got) != len(links) {
+		t.Fatalf("got %d links, want %d", len(got), len(links))
+	}
+	for i := range links {
+		if got[i] != links[i] {
+			t.Fatalf("link %d: got %v, want %v", i, got[i], links[i])
+		}
+	}
+
+	cfg := RenderConfig{OutputSize: 128}
+	want := renderLinks(links, cfg)
+	have := renderLinks(got, cfg)
+	if !bytes.Equal(want.Pix, have.Pix) {
+		t.Error("rendered image of re-imported links differs from the original")
+	}
+}
+
+// Test that malformed rows are skipped and counted rather than aborting.
+func TestImportCSV_SkipsMalformedRows(t *testing.T) {
+	input := strings.Join([]string{
+		"index,real,imag",
+		"0,1,0",
+		"1,not-a-number,0.5",
+		"2,1.5",
+		"3,1.25,0.75",
+		"x,1,1",
+	}, "\n")
+
+	links, skipped, err := ImportCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if skipped != 3 {
+		t.Errorf("got %d skipped rows, want 3", skipped)
+	}
+	want := []complex128{complex(1, 0), complex(1.25, 0.75)}
+	if len(links) != len(want) {
+		t.Fatalf("got %d links, want %d", len(links), len(want))
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("link %d: got %v, want %v", i, links[i], want[i])
+		}
+	}
+}
+
+// Test that saveLinksCSV replaces an existing file whole and leaves no
+// temp file beside it.
+func TestSaveLinksCSV_ReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "links.csv")
+	if err := os.WriteFile(filename, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	links := testutil.SpiralLinks(100)
+	if err := saveLinksCSV(links, filename); err != nil {
+		t.Fatalf("saveLinksCSV: %v", err)
+	}
+	got, err := loadLinksCSV(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(links) {
+		t.Errorf("got %d links back, want %d", len(got), len(links))
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("got %d entries in the directory, want only the CSV", len(entries))
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+// densityHeadroom is the brightness left above the background for the
+// strokes covering a pixel to share.
+const densityHeadroom = 255 - 30
+
+// densityGrid holds, per output pixel, how many of the chains' strokes
+// cover it, counted by stroking every segment once ahead of drawing.
+type densityGrid struct {
+	hits *hitBuffer
+}
+
+// newDensityGrid counts the segments of each chain, framed on view at
+// outputSize, stroked at the base line width.
+func newDensityGrid(chains [][]complex128, view Bounds, outputSize int, clip bool) *densityGrid {
+	hits := newHitBuffer(outputSize, 1)
+	strokes := newSegmentStroker(&hitPainter{hits: hits}, outputSize)
+	for _, links := range chains {
+		var prevX, prevY float64
+		for i, link := range links {
+			x, y := framePoint(link, view, outputSize, clip)
+			if i > 0 {
+				strokes.stroke(prevX, prevY, x, y, 0.5, color.White)
+			}
+			prevX, prevY = x, y
+		}
+	}
+	return &densityGrid{hits: hits}
+}
+
+// level returns the stroke brightness, out of 255, for the segment from
+// output pixel (x0, y0) to (x1, y1). Strokes add up where they overlap, so
+// those covering a pixel share densityHeadroom between them and the
+// additive blend stays below white. The share is taken at the most covered
+// pixel the segment touches, sampled along it, so it is never too large
+// anywhere along the segment; sparse pixels keep the full stroke. The level
+// never drops below 1, so segments in the densest pixels don't vanish.
+func (g *densityGrid) level(x0, y0, x1, y1 float64) float64 {
+	steps := int(math.Ceil(math.Hypot(x1-x0, y1-y0)))
+	var overlap float64
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(max(steps, 1))
+		x, y := int(math.Floor(x0+t*(x1-x0))), int(math.Floor(y0+t*(y1-y0)))
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if px, py := x+dx, y+dy; px >= 0 && py >= 0 && px < g.hits.size && py < g.hits.size {
+					overlap = math.Max(overlap, g.hits.density(px, py))
+				}
+			}
+		}
+	}
+	return math.Min(math.Max(densityHeadroom/overlap, 1), 255)
+}
+
+// dim scales the color channels of c by the density level of the segment
+// from (x0, y0) to (x1, y1).
+func (g *densityGrid) dim(c color.RGBA, x0, y0, x1, y1 float64) color.RGBA {
+	f := g.level(x0, y0, x1, y1) / 255
+	scaled := func(v uint8) uint8 { return uint8(math.Round(float64(v) * f)) }
+	return color.RGBA{scaled(c.R), scaled(c.G), scaled(c.B), c.A}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+)
+
+// DiffImages compares two images pixel by pixel. It returns a heatmap where
+// brighter, warmer pixels differ more, and a score in [0, 1] giving the mean
+// per-channel difference (0 means identical). Images of different sizes are
+// compared over the union of their bounds, with missing pixels treated as
+// transparent black.
+func DiffImages(a, b *image.RGBA) (*image.RGBA, float64) {
+	bounds := a.Bounds().Union(b.Bounds())
+	heatmap := image.NewRGBA(bounds)
+
+	pixelAt := func(img *image.RGBA, x, y int) color.RGBA {
+		if !(image.Point{x, y}.In(img.Bounds())) {
+			return color.RGBA{}
+		}
+		return img.RGBAAt(x, y)
+	}
+
+	var total float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pa := pixelAt(a, x, y)
+			pb := pixelAt(b, x, y)
+			d := (absDiff(pa.R, pb.R) + absDiff(pa.G, pb.G) +
+				absDiff(pa.B, pb.B) + absDiff(pa.A, pb.A)) / (4 * 255)
+			total += d
+			heatmap.SetRGBA(x, y, heatColor(d))
+		}
+	}
+
+	pixels := bounds.Dx() * bounds.Dy()
+	if pixels == 0 {
+		return heatmap, 0
+	}
+	return heatmap, total / float64(pixels)
+}
+
+func absDiff(a, b uint8) float64 {
+	return math.Abs(float64(a) - float64(b))
+}
+
+// heatColor maps v in [0, 1] onto a black→red→yellow→white ramp.
+func heatColor(v float64) color.RGBA {
+	v = math.Min(math.Max(v, 0), 1)
+	channel := func(start float64) uint8 {
+		return uint8(math.Round(255 * math.Min(math.Max((v-start)*3, 0), 1)))
+	}
+	return color.RGBA{channel(0), channel(1.0 / 3), channel(2.0 / 3), 255}
+}
+
+// loadPNG decodes a PNG file into an RGBA image.
+func loadPNG(filename string) (*image.RGBA, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"image"
+	"image/draw"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that an image matches itself exactly and differs from a shifted copy.
+func TestDiffImages(t *testing.T) {
+	img := renderLinks(testutil.SpiralLinks(2000), RenderConfig{OutputSize: 128})
+
+	heatmap, score := DiffImages(img, img)
+	if score != 0 {
+		t.Errorf("self-diff score: got %f, want 0", score)
+	}
+	if heatmap.Bounds() != img.Bounds() {
+		t.Errorf("heatmap bounds: got %v, want %v", heatmap.Bounds(), img.Bounds())
+	}
+
+	shifted := image.NewRGBA(img.Bounds())
+	draw.Draw(shifted, img.Bounds().Add(image.Pt(3, 0)), img, image.Point{}, draw.Src)
+
+	heatmap, score = DiffImages(img, shifted)
+	if score <= 0 {
+		t.Errorf("shifted diff score: got %f, want > 0", score)
+	}
+
+	hot := 0
+	for i := 0; i < len(heatmap.Pix); i += 4 {
+		if heatmap.Pix[i] > 0 {
+			hot++
+		}
+	}
+	if hot == 0 {
+		t.Error("expected heatmap to highlight differing pixels")
+	}
+}
@@ -10,19 +10,9 @@ import (
 	"testing"
 
 	"github.com/llgcode/draw2d/draw2dimg"
-)
 
-// generateTestLinks creates a spiral of complex points for testing
-func generateTestLinks(n int) []complex128 {
-	links := make([]complex128, n)
-	for i := 0; i < n; i++ {
-		t := float64(i) / float64(n)
-		r := t * 10
-		theta := t * 20 * 2 * math.Pi
-		links[i] = cmplx.Rect(r, theta)
-	}
-	return links
-}
+	"zeta-scale-go/internal/testutil"
+)
 
 // generateDownsampledLinks creates a spiral pattern while downsampling inline
 func generateDownsampledLinks(n int, outputSize int, aggressiveness float64) []complex128 {
@@ -134,14 +124,14 @@ func BenchmarkDownsampleVsNoDownsample(b *testing.B) {
 
 	for _, tc := range testCases {
 		// Generate test data
-		links := generateTestLinks(tc.size)
+		links := testutil.SpiralLinks(tc.size)
 		outputSize := 2048 // Standard output size
 
 		b.Run(tc.name, func(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
 				if tc.aggressiveness > 0 {
-					downsampleComplex(links, outputSize, tc.aggressiveness, false)
+					downsampleComplex(links, outputSize, tc.aggressiveness, DownsampleOptions{}, false)
 				} else {
 					// Just iterate through the links to simulate "no downsampling"
 					for j := 0; j < len(links); j++ {
@@ -153,7 +143,7 @@ func BenchmarkDownsampleVsNoDownsample(b *testing.B) {
 
 		// Log memory stats after each test case
 		b.ReportMetric(float64(len(links)*16)/1024, "KB_before") // 16 bytes per complex128
-		result := downsampleComplex(links, outputSize, tc.aggressiveness, false)
+		result := downsampleComplex(links, outputSize, tc.aggressiveness, DownsampleOptions{}, false)
 		b.ReportMetric(float64(len(result)*16)/1024, "KB_after")
 		b.ReportMetric(float64(len(links))/float64(len(result)), "reduction_ratio")
 	}
@@ -203,13 +193,13 @@ func BenchmarkDownsampleComplexEdgeCases(b *testing.B) {
 		b.Run(tc.name, func(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				downsampleComplex(links, outputSize, 0.5, false)
+				downsampleComplex(links, outputSize, 0.5, DownsampleOptions{}, false)
 			}
 		})
 
 		// Log memory stats
 		b.ReportMetric(float64(len(links)*16)/1024, "KB_before")
-		result := downsampleComplex(links, outputSize, 0.5, false)
+		result := downsampleComplex(links, outputSize, 0.5, DownsampleOptions{}, false)
 		b.ReportMetric(float64(len(result)*16)/1024, "KB_after")
 		b.ReportMetric(float64(len(links))/float64(len(result)), "reduction_ratio")
 	}
@@ -233,15 +223,15 @@ func BenchmarkInlineVsPostDownsample(b *testing.B) {
 
 	for _, tc := range testCases {
 		b.Run("Post_"+tc.name, func(b *testing.B) {
-			links := generateTestLinks(tc.size)
+			links := testutil.SpiralLinks(tc.size)
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				downsampleComplex(links, outputSize, tc.aggressiveness, false)
+				downsampleComplex(links, outputSize, tc.aggressiveness, DownsampleOptions{}, false)
 			}
 			// Report memory for first run
 			if i := 0; i == 0 {
 				b.ReportMetric(float64(len(links)*16)/1024, "KB_initial")
-				result := downsampleComplex(links, outputSize, tc.aggressiveness, false)
+				result := downsampleComplex(links, outputSize, tc.aggressiveness, DownsampleOptions{}, false)
 				b.ReportMetric(float64(len(result)*16)/1024, "KB_final")
 				b.ReportMetric(float64(len(links))/float64(len(result)), "reduction_ratio")
 			}
@@ -268,18 +258,18 @@ func BenchmarkDownsampleComplex(b *testing.B) {
 	outputSize := 2048
 
 	for _, size := range sizes {
-		links := generateTestLinks(size)
+		links := testutil.SpiralLinks(size)
 
 		for _, agg := range aggressiveness {
 			b.Run("Serial/Size="+formatInt(size)+"/Agg="+formatFloat(agg), func(b *testing.B) {
 				for i := 0; i < b.N; i++ {
-					downsampleComplexSerial(links, outputSize, agg, false)
+					downsampleComplexSerial(links, outputSize, agg, DownsampleOptions{}, false)
 				}
 			})
 
 			b.Run("Parallel/Size="+formatInt(size)+"/Agg="+formatFloat(agg), func(b *testing.B) {
 				for i := 0; i < b.N; i++ {
-					downsampleComplex(links, outputSize, agg, false)
+					downsampleComplex(links, outputSize, agg, DownsampleOptions{}, false)
 				}
 			})
 		}
@@ -313,10 +303,10 @@ func BenchmarkFullPipelineFPS(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		// Calculate spiral (already parallel)
-		_, links := calculateSpiralPartialSums(s)
+		links := calculateSpiralPartialSums(s, ChunkConfig{}).Links
 
 		// Downsample using parallel version
-		links = downsampleComplex(links, outputSize, aggressiveness, false)
+		links = downsampleComplex(links, outputSize, aggressiveness, DownsampleOptions{}, false)
 
 		// Create a dummy image (we don't actually save it in the benchmark)
 		img := image.NewRGBA(image.Rect(0, 0, outputSize, outputSize))
@@ -378,7 +368,7 @@ func BenchmarkFullPipelineFPSNoDownsample(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		// Calculate spiral (already parallel)
-		_, links := calculateSpiralPartialSums(s)
+		links := calculateSpiralPartialSums(s, ChunkConfig{}).Links
 
 		// Create a dummy image (we don't actually save it in the benchmark)
 		img := image.NewRGBA(image.Rect(0, 0, outputSize, outputSize))
@@ -427,3 +417,25 @@ func BenchmarkFullPipelineFPSNoDownsample(b *testing.B) {
 	fps := float64(b.N) / b.Elapsed().Seconds()
 	b.ReportMetric(fps, "fps")
 }
+
+// BenchmarkDownsampleThreshold times both downsampling paths at half and
+// twice the calibrated threshold; the serial path should win below it and
+// the parallel path above it.
+func BenchmarkDownsampleThreshold(b *testing.B) {
+	threshold := calibrateParallelThreshold(2048, 0.5)
+	b.Logf("calibrated threshold: %d links on %d CPUs", threshold, runtime.NumCPU())
+
+	for _, n := range []int{threshold / 2, threshold * 2} {
+		links := testutil.SpiralLinks(n)
+		b.Run(fmt.Sprintf("serial-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				downsampleComplexSerial(links, 2048, 0.5, DownsampleOptions{}, false)
+			}
+		})
+		b.Run(fmt.Sprintf("parallel-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				downsampleComplexParallel(links, 2048, 0.5, DownsampleOptions{}, false)
+			}
+		})
+	}
+}
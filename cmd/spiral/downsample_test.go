@@ -1,8 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"log"
 	"math"
+	"math/cmplx"
+	"strings"
 	"testing"
+
+	"zeta-scale-go/internal/testutil"
 )
 
 func TestDownsample(t *testing.T) {
@@ -52,7 +58,7 @@ func TestDownsampleComplex_SamePixel(t *testing.T) {
 	}
 
 	// With a high resolution and aggressiveness=4.0 (maximum), these nearly identical values should be averaged
-	got := downsampleComplex(links, 2048, 4.0, true)
+	got := downsampleComplex(links, 2048, 4.0, DownsampleOptions{}, true)
 
 	// With high aggressiveness, we expect a single averaged point
 	if len(got) != 1 {
@@ -86,7 +92,7 @@ func TestDownsampleComplex_Interpolate(t *testing.T) {
 	}
 
 	// With aggressiveness=4.0 (maximum), we expect fewer interpolated points
-	got := downsampleComplex(links, 100, 4.0, false)
+	got := downsampleComplex(links, 100, 4.0, DownsampleOptions{}, false)
 
 	// We expect some points, but not too many due to high aggressiveness
 	if len(got) < 2 {
@@ -104,3 +110,150 @@ func TestDownsampleComplex_Interpolate(t *testing.T) {
 		t.Errorf("last point mismatch: got %v, want %v", got[len(got)-1], links[len(links)-1])
 	}
 }
+
+// Test that out-of-range aggressiveness is clamped to the supported range
+// with a warning, and that in-range values pass through silently.
+func TestDownsampleComplex_ClampAggressiveness(t *testing.T) {
+	var buf bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(prev)
+
+	cases := []struct {
+		in, want float64
+		warn     bool
+	}{
+		{-1, MinAggressiveness, true},
+		{9, MaxAggressiveness, true},
+		{MaxAggressiveness, MaxAggressiveness, false},
+		{2.5, 2.5, false},
+	}
+	for _, c := range cases {
+		buf.Reset()
+		if got := clampAggressiveness(c.in); got != c.want {
+			t.Errorf("clampAggressiveness(%v) = %v, want %v", c.in, got, c.want)
+		}
+		if warned := strings.Contains(buf.String(), "Warning"); warned != c.warn {
+			t.Errorf("clampAggressiveness(%v) warned = %v, want %v", c.in, warned, c.warn)
+		}
+	}
+
+	// Downsampling with an out-of-range value matches the clamped value.
+	links := testutil.SpiralLinks(5000)
+	over := downsampleComplex(links, 256, 9, DownsampleOptions{}, false)
+	atMax := downsampleComplex(links, 256, MaxAggressiveness, DownsampleOptions{}, false)
+	if len(over) != len(atMax) {
+		t.Fatalf("got %d links for aggressiveness 9, want %d as at the maximum", len(over), len(atMax))
+	}
+	for i := range over {
+		if over[i] != atMax[i] {
+			t.Fatalf("link %d differs: %v vs %v", i, over[i], atMax[i])
+		}
+	}
+}
+
+// Test that the serial and parallel paths agree on either side of the switch
+// threshold, so moving the threshold never changes the rendered shape.
+func TestDownsampleComplex_ThresholdPathsMatch(t *testing.T) {
+	for _, n := range []int{ParallelDownsampleThreshold - 1, ParallelDownsampleThreshold, 2 * ParallelDownsampleThreshold} {
+		links := testutil.SpiralLinks(n)
+		serial := downsampleComplexSerial(links, 1024, 0.5, DownsampleOptions{}, false)
+		parallel := downsampleComplexParallel(links, 1024, 0.5, DownsampleOptions{}, false)
+
+		if serial[0] != parallel[0] {
+			t.Errorf("n=%d: first point differs: serial %v, parallel %v", n, serial[0], parallel[0])
+		}
+		if last := len(serial) - 1; !cmplxEquals(serial[last], parallel[len(parallel)-1], 1e-9) {
+			t.Errorf("n=%d: last point differs: serial %v, parallel %v", n, serial[last], parallel[len(parallel)-1])
+		}
+		// Chunk boundaries may split or re-interpolate a group or two.
+		if diff := math.Abs(float64(len(serial) - len(parallel))); diff > 0.01*float64(len(serial)) {
+			t.Errorf("n=%d: got %d parallel points, want within 1%% of %d serial points", n, len(parallel), len(serial))
+		}
+	}
+}
+
+// Test that cubic gap interpolation along a sparsely sampled arc stays
+// closer to the circle than linear interpolation, and falls back to linear
+// at the ends of the chain.
+func TestDownsampleComplexSerial_CubicInterpolation(t *testing.T) {
+
+	// Twelve links around three quarters of the unit circle leave gaps of
+	// tens of pixels at this size.
+	var links []complex128
+	for i := 0; i < 12; i++ {
+		links = append(links, cmplx.Rect(1, 1.5*math.Pi*float64(i)/11))
+	}
+
+	// Measure the interior gaps, where cubic has both neighbors.
+	lo, hi := cmplx.Phase(links[1]), cmplx.Phase(links[len(links)-2])+2*math.Pi
+	maxDeviation := func(points []complex128) float64 {
+		var worst float64
+		for _, p := range points {
+			if angle := math.Mod(cmplx.Phase(p)+2*math.Pi, 2*math.Pi); angle >= lo && angle <= hi {
+				worst = math.Max(worst, math.Abs(cmplx.Abs(p)-1))
+			}
+		}
+		return worst
+	}
+
+	linear := downsampleComplexSerial(links, 256, 0, DownsampleOptions{Interpolation: InterpLinear}, false)
+	cubic := downsampleComplexSerial(links, 256, 0, DownsampleOptions{Interpolation: InterpCubic}, false)
+
+	if len(linear) <= len(links) || len(cubic) != len(linear) {
+		t.Fatalf("got %d linear and %d cubic points from %d links, want the same number of interpolated points in each",
+			len(linear), len(cubic), len(links))
+	}
+	linearErr, cubicErr := maxDeviation(linear), maxDeviation(cubic)
+	t.Logf("max distance from the circle: linear %g, cubic %g", linearErr, cubicErr)
+	if cubicErr >= linearErr/4 {
+		t.Errorf("cubic deviation %g is not well below linear %g", cubicErr, linearErr)
+	}
+
+	// The first and last gaps each miss an outer neighbor, so they stay linear.
+	n := len(links)
+	for _, i := range []int{1, n - 1} {
+		if got, want := gapPoint(links, 0, n, i, 0.5, InterpCubic), (links[i-1]+links[i])/2; got != want {
+			t.Errorf("gap before link %d: got %v, want the linear midpoint %v", i, got, want)
+		}
+	}
+}
+
+// Test that with PreserveExtremes the downsampled bounds equal the original
+// ones exactly, on the serial and parallel paths, with cubic gaps that can
+// overshoot, and when everything collapses to one average.
+func TestDownsampleComplex_PreserveExtremes(t *testing.T) {
+	defer func(orig int) { ParallelDownsampleThreshold = orig }(ParallelDownsampleThreshold)
+
+	links := testutil.SpiralLinks(50_000)
+	minX, maxX, minY, maxY := computeBounds(links)
+	want := [4]float64{minX, maxX, minY, maxY}
+
+	for _, interp := range []string{InterpLinear, InterpCubic} {
+		for _, threshold := range []int{len(links) + 1, 1} {
+			ParallelDownsampleThreshold = threshold
+			for _, aggressiveness := range []float64{0, 2, MaxAggressiveness} {
+				plain := downsampleComplex(links, 256, aggressiveness, DownsampleOptions{Interpolation: interp}, false)
+				kept := downsampleComplex(links, 256, aggressiveness, DownsampleOptions{Interpolation: interp, PreserveExtremes: true}, false)
+
+				minX, maxX, minY, maxY := computeBounds(kept)
+				if got := [4]float64{minX, maxX, minY, maxY}; got != want {
+					t.Errorf("%s, threshold %d, aggressiveness %v: got bounds %v, want %v",
+						interp, threshold, aggressiveness, got, want)
+				}
+				if len(kept) > len(plain)+4 {
+					t.Errorf("%s, threshold %d, aggressiveness %v: got %d points, want at most 4 more than %d",
+						interp, threshold, aggressiveness, len(kept), len(plain))
+				}
+			}
+		}
+	}
+
+	// A spread small enough to average to a single point keeps its extremes.
+	tiny := []complex128{0, 1e-6, 1e-6i, 2e-6 + 1e-6i}
+	got := downsampleComplexSerial(tiny, 256, MaxAggressiveness, DownsampleOptions{PreserveExtremes: true}, false)
+	minX, maxX, minY, maxY = computeBounds(got)
+	if minX != 0 || maxX != 2e-6 || minY != 0 || maxY != 1e-6 {
+		t.Errorf("got bounds X [%v, %v] Y [%v, %v] from %v, want X [0, 2e-06] Y [0, 1e-06]", minX, maxX, minY, maxY, got)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Test that acceleration reaches a target accuracy at the first nontrivial
+// zero with an order of magnitude fewer terms than the plain partial sum.
+func TestEtaAccelerated(t *testing.T) {
+	// η vanishes wherever ζ does, so the exact value here is 0.
+	s := complex(0.5, 14.134725141734693)
+	const target = 1e-3
+	const terms = 30
+
+	if got := cmplx.Abs(EtaAccelerated(s, terms)); got > target {
+		t.Errorf("accelerated with %d terms: |η| = %e, want <= %e", terms, got, target)
+	}
+	if got := cmplx.Abs(Eta(s, 10*terms)); got <= target {
+		t.Errorf("plain sum with %d terms already reaches |η| = %e; acceleration shows no gain", 10*terms, got)
+	}
+
+	// Away from a zero, both agree with ζ(2)(1 - 2^{-1}) = π²/12.
+	want := complex(0.8224670334241132, 0)
+	if got := EtaAccelerated(2, terms); !cmplxEquals(got, want, 1e-12) {
+		t.Errorf("EtaAccelerated(2): got %v, want %v", got, want)
+	}
+	if got := Eta(2, 100000); !cmplxEquals(got, want, 1e-9) {
+		t.Errorf("Eta(2): got %v, want %v", got, want)
+	}
+}
+
+// Test that the eta continuation on the critical line agrees with a tightly
+// converged Euler-Maclaurin value and is rotated onto the real axis by the
+// Riemann-Siegel theta, as ζ(1/2 + it) must be.
+func TestZetaEta_CriticalLine(t *testing.T) {
+	for _, height := range []float64{14.134725141734693, 20, 50} {
+		s := complex(0.5, height)
+		got, ok := ZetaEta(s)
+		if !ok {
+			t.Fatalf("ZetaEta(%v) declined a point in the strip", s)
+		}
+
+		reference, _, residual := ZetaAdaptive(s, 1e-8)
+		if !cmplxEquals(got, reference, 1e-6+residual) {
+			t.Errorf("t=%v: eta continuation %v, Euler-Maclaurin %v", height, got, reference)
+		}
+		if z := cmplx.Rect(1, RiemannSiegelTheta(height)) * got; math.Abs(imag(z)) > 1e-6 {
+			t.Errorf("t=%v: e^{iθ}ζ = %v, want a real Z(t)", height, z)
+		}
+	}
+
+	if _, ok := ZetaEta(complex(2, 0)); ok {
+		t.Error("ZetaEta(2) should decline a point outside the strip")
+	}
+	if got, want := ZetaByMethod(complex(2, 0), MethodAuto), Zeta(complex(2, 0)); got != want {
+		t.Errorf("auto method outside the strip: got %v, want Euler-Maclaurin %v", got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"slices"
+)
+
+// preserveExtremes returns downsampled, a downsampling of links, adjusted
+// to have the same bounds as links when opts.PreserveExtremes is set. Points
+// outside those bounds (cubic gap filling can overshoot) are clamped into
+// them, and each extreme link the downsampled chain falls short of is
+// inserted after the point nearest it, normally the average of the group it
+// was merged into, so the chain keeps its order and gains at most four
+// points.
+func preserveExtremes[T linkValue](links []T, downsampled []complex128, opts DownsampleOptions) []complex128 {
+	if !opts.PreserveExtremes || len(links) == 0 {
+		return downsampled
+	}
+	minX, maxX, minY, maxY := computeBounds(links)
+	bounds := [4]float64{minX, maxX, minY, maxY}
+	for i, p := range downsampled {
+		downsampled[i] = complex(
+			math.Min(math.Max(real(p), minX), maxX),
+			math.Min(math.Max(imag(p), minY), maxY),
+		)
+	}
+
+	for k, bound := range bounds {
+		gotMinX, gotMaxX, gotMinY, gotMaxY := computeBounds(downsampled)
+		if [4]float64{gotMinX, gotMaxX, gotMinY, gotMaxY}[k] == bound {
+			continue
+		}
+		// bounds holds the X bounds, then the Y bounds.
+		var extreme complex128
+		for _, link := range links {
+			p := complex128(link)
+			if (k < 2 && real(p) == bound) || (k >= 2 && imag(p) == bound) {
+				extreme = p
+				break
+			}
+		}
+		nearest := 0
+		for i, p := range downsampled {
+			if cmplx.Abs(p-extreme) < cmplx.Abs(downsampled[nearest]-extreme) {
+				nearest = i
+			}
+		}
+		downsampled = slices.Insert(downsampled, nearest+1, extreme)
+	}
+	return downsampled
+}
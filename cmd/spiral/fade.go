@@ -0,0 +1,20 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+// fadeLevel returns the alpha factor RenderConfig.Fade applies to the
+// segment ending at link i of n: 1 for the last link, decaying as
+// exp(-age/tau) with the link's distance from the end.
+func fadeLevel(i, n int, tau float64) float64 {
+	return math.Exp(-float64(n-1-i) / tau)
+}
+
+// fade scales c, including its alpha, by the fade level of link i of n.
+func fade(c color.RGBA, i, n int, tau float64) color.RGBA {
+	f := fadeLevel(i, n, tau)
+	scaled := func(v uint8) uint8 { return uint8(math.Round(float64(v) * f)) }
+	return color.RGBA{scaled(c.R), scaled(c.G), scaled(c.B), scaled(c.A)}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log"
+	"strconv"
+)
+
+// maxFloatChars bounds the length of a float64 written as the shortest
+// round-trip decimal, by strconv 'g' or by encoding/json.
+const maxFloatChars = 26
+
+// estimateCSVSize bounds the size of ExportCSV's output for n links.
+func estimateCSVSize(n int) int64 {
+	row := int64(len(strconv.Itoa(n)) + 2*maxFloatChars + 3)
+	return int64(len("index,real,imag\n")) + int64(n)*row
+}
+
+// estimateThreeJSSize bounds the size of ExportThreeJSON's output for n
+// links: three numbers per link, the last always 0, plus the bounds.
+func estimateThreeJSSize(n int) int64 {
+	return 256 + int64(n)*(2*maxFloatChars+4)
+}
+
+// estimateNDJSONSize bounds the size of ExportNDJSON's output for n links.
+func estimateNDJSONSize(n int) int64 {
+	line := int64(len(`{"i":,"re":,"im":}`+"\n") + len(strconv.Itoa(n)) + 2*maxFloatChars)
+	return int64(n) * line
+}
+
+// estimateParquetSize bounds the size of ExportParquet's output for n
+// links: two plain float64 columns, with page headers and the footer well
+// within a byte per link plus a fixed allowance.
+func estimateParquetSize(n int) int64 {
+	return 1024 + int64(n)*17
+}
+
+// fitToFileSize downsamples links with rising aggressiveness until
+// estimate, the size of the largest file to be written for a given link
+// count, is at most maxBytes, downsampling as opts selects. It returns links unchanged and an
+// aggressiveness of -1 when they already fit, and the most aggressive
+// result with ok false when nothing does.
+func fitToFileSize(links []complex128, maxBytes int64, estimate func(n int) int64, outputSize int, opts DownsampleOptions) (fitted []complex128, aggressiveness float64, ok bool) {
+	if estimate(len(links)) <= maxBytes {
+		return links, -1, true
+	}
+	for aggressiveness = MinAggressiveness; aggressiveness <= MaxAggressiveness; aggressiveness += 0.5 {
+		fitted = downsampleComplex(links, outputSize, aggressiveness, opts, false)
+		if estimate(len(fitted)) <= maxBytes {
+			return fitted, aggressiveness, true
+		}
+	}
+	log.Printf("Warning: %d links still need about %d bytes at the maximum aggressiveness", len(fitted), estimate(len(fitted)))
+	return fitted, MaxAggressiveness, false
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestGammaComplex(t *testing.T) {
+	testCases := []struct {
+		z    complex128
+		want complex128
+	}{
+		{1, 1},
+		{0.5, complex(math.Sqrt(math.Pi), 0)},
+		{5, 24},
+		{-0.5, complex(-2*math.Sqrt(math.Pi), 0)},
+		{complex(1, 1), complex(0.49801566811835604, -0.15494982830181067)},
+	}
+	for _, tc := range testCases {
+		if got := GammaComplex(tc.z); !cmplxEquals(got, tc.want, 1e-12) {
+			t.Errorf("Γ(%v): got %v, want %v", tc.z, got, tc.want)
+		}
+	}
+
+	// |Γ(1+i)|² = π/sinh(π), independent of the reference digits above.
+	got := GammaComplex(complex(1, 1))
+	if want := math.Sqrt(math.Pi / math.Sinh(math.Pi)); !floatEquals(cmplx.Abs(got), want, 1e-12) {
+		t.Errorf("|Γ(1+i)|: got %v, want %v", cmplx.Abs(got), want)
+	}
+	if want := -0.30164032046763894; !floatEquals(cmplx.Phase(got), want, 1e-12) {
+		t.Errorf("arg Γ(1+i): got %v, want %v", cmplx.Phase(got), want)
+	}
+
+	if got := GammaComplex(-2); !cmplx.IsInf(got) {
+		t.Errorf("Γ(-2): got %v, want a pole", got)
+	}
+}
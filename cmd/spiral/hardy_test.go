@@ -0,0 +1,187 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+	"time"
+)
+
+// Test that NearestZero snaps to the closer of the zeros bracketing t.
+func TestNearestZero(t *testing.T) {
+	const (
+		firstZero  = 14.134725
+		secondZero = 21.022040
+	)
+
+	testCases := []struct {
+		t    float64
+		want float64
+	}{
+		{20, secondZero}, // 1.02 above vs 5.87 below
+		{16, firstZero},  // 1.87 below vs 5.02 above
+		{5, firstZero},   // nothing below the first zero
+	}
+
+	// Below t ≈ 20 the Riemann-Siegel formula is good to a few 1e-4 and
+	// the zeros are only listed to six places.
+	const tolerance = 5e-3
+
+	for _, tc := range testCases {
+		got, dist := NearestZero(tc.t)
+		if !floatEquals(got, tc.want, tolerance) {
+			t.Errorf("NearestZero(%v): got %f, want %f", tc.t, got, tc.want)
+		}
+		if !floatEquals(dist, math.Abs(tc.want-tc.t), tolerance) {
+			t.Errorf("NearestZero(%v): got distance %f, want %f", tc.t, dist, math.Abs(tc.want-tc.t))
+		}
+	}
+}
+
+// Test that FindZeros recovers the first ten zeros in order and flags the
+// closest pair among them.
+func TestFindZeros_ClosePairs(t *testing.T) {
+	want := []float64{
+		14.134725, 21.022040, 25.010858, 30.424876, 32.935062,
+		37.586178, 40.918719, 43.327073, 48.005151, 49.773832,
+	}
+	const tolerance = 5e-3
+
+	scan := FindZeros(10, 50, 2.0)
+	if len(scan.Zeros) != len(want) {
+		t.Fatalf("got %d zeros %v, want %d", len(scan.Zeros), scan.Zeros, len(want))
+	}
+	for i, z := range scan.Zeros {
+		if !floatEquals(z, want[i], tolerance) {
+			t.Errorf("zero %d: got %f, want %f", i, z, want[i])
+		}
+	}
+
+	if len(scan.Gaps) != len(want)-1 {
+		t.Fatalf("got %d gaps, want %d", len(scan.Gaps), len(want)-1)
+	}
+	for i, gap := range scan.Gaps {
+		if gap <= 0 {
+			t.Errorf("gap %d is not positive: %f", i, gap)
+		}
+	}
+
+	// Only 48.01 and 49.77, 1.77 apart, are closer than 2.
+	if len(scan.ClosePairs) != 1 {
+		t.Fatalf("got close pairs %v, want exactly one", scan.ClosePairs)
+	}
+	if pair := scan.ClosePairs[0]; !floatEquals(pair[0], want[8], tolerance) || !floatEquals(pair[1], want[9], tolerance) {
+		t.Errorf("got close pair %v, want [%f %f]", pair, want[8], want[9])
+	}
+	if !floatEquals(scan.MinGap, want[9]-want[8], 2*tolerance) {
+		t.Errorf("got min gap %f, want %f", scan.MinGap, want[9]-want[8])
+	}
+}
+
+// Test that at small heights, where the asymptotic expansion has broken
+// down, RiemannSiegelTheta still matches the exact phase.
+func TestRiemannSiegelTheta_SmallT(t *testing.T) {
+	// θ(3) = Im log Γ(1/4 + 3i/2) - (3/2) log π.
+	const want = -2.9945646960108254
+
+	got := RiemannSiegelTheta(3)
+	if !floatEquals(got, want, 1e-12) {
+		t.Errorf("RiemannSiegelTheta(3): got %.16f, want %.16f", got, want)
+	}
+	guardedErr := math.Abs(got - want)
+	fullErr := math.Abs(riemannSiegelThetaAsymptotic(3) - want)
+	if guardedErr >= fullErr {
+		t.Errorf("guarded error %g is not below the full expansion's %g", guardedErr, fullErr)
+	}
+
+	// Both forms agree where they hand over.
+	if diff := math.Abs(riemannSiegelThetaExact(thetaAsymptoticMin) - riemannSiegelThetaAsymptotic(thetaAsymptoticMin)); diff > 1e-12 {
+		t.Errorf("exact and asymptotic θ differ by %g at t = %v", diff, thetaAsymptoticMin)
+	}
+}
+
+// Test that ZCurve over [14, 26] crosses zero once for each of the zeros
+// 14.13, 21.02 and 25.01, and that the plot draws the curve.
+func TestZCurve_Crossings(t *testing.T) {
+	ts, zs := ZCurve(14, 26, 0.01)
+	if len(ts) != len(zs) || len(ts) != 1201 {
+		t.Fatalf("got %d heights and %d values, want 1201 of each", len(ts), len(zs))
+	}
+	if ts[0] != 14 || !floatEquals(ts[len(ts)-1], 26, 1e-9) {
+		t.Errorf("got t from %v to %v, want 14 to 26", ts[0], ts[len(ts)-1])
+	}
+
+	crossings := 0
+	for i := 1; i < len(zs); i++ {
+		if math.Signbit(zs[i]) != math.Signbit(zs[i-1]) {
+			crossings++
+		}
+	}
+	if crossings != 3 {
+		t.Errorf("got %d zero crossings in [14, 26], want 3", crossings)
+	}
+
+	if env := ZEnvelope(20); !floatEquals(env, math.Sqrt(math.Log(20/(2*math.Pi))), 1e-15) {
+		t.Errorf("ZEnvelope(20): got %v", env)
+	}
+
+	img := renderZCurve(ts, zs, 64)
+	white := 0
+	for i := 0; i < len(img.Pix); i += 4 {
+		if img.Pix[i] > 200 && img.Pix[i+2] > 200 {
+			white++
+		}
+	}
+	if white < 64 {
+		t.Errorf("got %d white pixels, want the Z(t) curve across the plot", white)
+	}
+}
+
+// Test that ZCurve returns no samples for a non-positive step or an
+// inverted range instead of sizing its slices from them.
+func TestZCurve_EmptyRange(t *testing.T) {
+	for _, c := range []struct{ tMin, tMax, step float64 }{
+		{14, 26, 0},
+		{14, 26, -0.1},
+		{26, 14, 0.1},
+		{14, 26, math.NaN()},
+	} {
+		if ts, zs := ZCurve(c.tMin, c.tMax, c.step); len(ts) != 0 || len(zs) != 0 {
+			t.Errorf("ZCurve(%v, %v, %v): got %d samples, want none", c.tMin, c.tMax, c.step, len(ts))
+		}
+	}
+}
+
+// Test that HardyZ at the default -imag height takes about sqrt(t/2π)
+// terms rather than |s|: a full -zplot curve finishes quickly, crosses zero
+// about as often as the zero density log(t/2π)/2π predicts, and agrees with
+// ζ summed directly.
+func TestHardyZ_RealisticHeight(t *testing.T) {
+	const (
+		height = 6_300_000.0
+		half   = 12.0
+		points = 2048
+	)
+
+	start := time.Now()
+	ts, zs := ZCurve(height-half, height+half, 2*half/points)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ZCurve of %d points at t = %g took %v, want well under 2s", len(ts), height, elapsed)
+	}
+
+	crossings := 0
+	for i := 1; i < len(zs); i++ {
+		if math.Signbit(zs[i]) != math.Signbit(zs[i-1]) {
+			crossings++
+		}
+	}
+	expected := 2 * half * math.Log(height/(2*math.Pi)) / (2 * math.Pi)
+	if c := float64(crossings); c < 0.7*expected || c > 1.3*expected {
+		t.Errorf("got %d zero crossings in %g units of t, want about %.0f", crossings, 2*half, expected)
+	}
+
+	want := real(cmplx.Rect(1, RiemannSiegelTheta(height)) * Zeta(complex(0.5, height)))
+	if got := HardyZ(height); !floatEquals(got, want, 1e-3) {
+		t.Errorf("HardyZ(%g): got %v, want %v from ζ", height, got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"sync/atomic"
+
+	"github.com/golang/freetype/raster"
+)
+
+// hitUnit is one full-opacity hit in a hitBuffer count.
+const hitUnit = 0xffff
+
+// hitBuffer counts, for each pixel of a drawSize x drawSize layer, how many
+// strokes have covered it, weighted by their coverage and alpha in
+// hitUnits. Workers add to it concurrently, and the heat palette maps the
+// counts through its colormap, so a pixel's color depends only on how often
+// it was crossed and not on how the links were split among workers. Counts
+// saturate at about 65,000 hits, far past where the colormap turns white.
+type hitBuffer struct {
+	size, scale int
+	counts      []atomic.Uint32
+}
+
+// newHitBuffer returns an empty hitBuffer for an outputSize image drawn at
+// scale times its size.
+func newHitBuffer(outputSize, scale int) *hitBuffer {
+	size := outputSize * scale
+	return &hitBuffer{size: size, scale: scale, counts: make([]atomic.Uint32, size*size)}
+}
+
+// add adds n hitUnits to the pixel at (x, y), ignoring points outside the
+// layer.
+func (h *hitBuffer) add(x, y int, n uint32) {
+	if x < 0 || y < 0 || x >= h.size || y >= h.size || n == 0 {
+		return
+	}
+	count := &h.counts[y*h.size+x]
+	for {
+		old := count.Load()
+		sum := old + n
+		if sum < old {
+			sum = math.MaxUint32
+		}
+		if sum == old || count.CompareAndSwap(old, sum) {
+			return
+		}
+	}
+}
+
+// addSpans counts rasterized spans painted with the given 16-bit alpha.
+func (h *hitBuffer) addSpans(ss []raster.Span, alpha uint32) {
+	for _, s := range ss {
+		n := s.Alpha * alpha / 0xffff
+		for x := s.X0; x < s.X1; x++ {
+			h.add(x, s.Y, n)
+		}
+	}
+}
+
+// density returns the hits, in full-opacity hits, of output pixel (x, y):
+// the mean over the scale x scale block of layer pixels it covers.
+func (h *hitBuffer) density(x, y int) float64 {
+	var sum float64
+	for sy := 0; sy < h.scale; sy++ {
+		row := (y*h.scale + sy) * h.size
+		for sx := 0; sx < h.scale; sx++ {
+			sum += float64(h.counts[row+x*h.scale+sx].Load())
+		}
+	}
+	return sum / float64(h.scale*h.scale) / hitUnit
+}
+
+// hitPainter counts the spans it is given into a hitBuffer without drawing
+// them.
+type hitPainter struct {
+	hits  *hitBuffer
+	alpha uint32
+}
+
+// SetColor satisfies draw2dimg.Painter.
+func (p *hitPainter) SetColor(c color.Color) {
+	_, _, _, p.alpha = c.RGBA()
+}
+
+// Paint satisfies raster.Painter.
+func (p *hitPainter) Paint(ss []raster.Span, done bool) {
+	p.hits.addSpans(ss, p.alpha)
+}
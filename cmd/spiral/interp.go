@@ -0,0 +1,34 @@
+package main
+
+// Gap interpolation methods accepted by -interp.
+const (
+	// InterpLinear fills a pixel gap with points on the straight line
+	// between the links on either side.
+	InterpLinear = "linear"
+	// InterpCubic fills it along the Catmull-Rom spline through those links
+	// and their outer neighbors, following the curve across wide gaps.
+	InterpCubic = "cubic"
+)
+
+// validInterpolation reports whether name is a known interpolation method.
+func validInterpolation(name string) bool {
+	return name == InterpLinear || name == InterpCubic
+}
+
+// gapPoint returns the point a fraction t of the way across the gap from
+// links[i-1] to links[i], where links[lo:hi] is the range being downsampled,
+// filled as interp selects. Under InterpCubic it follows the Catmull-Rom spline with links[i-2] and
+// links[i+1] as control points; where either falls outside the range it
+// falls back to the straight line.
+func gapPoint[T linkValue](links []T, lo, hi, i int, t float64, interp string) complex128 {
+	p1, p2 := complex128(links[i-1]), complex128(links[i])
+	if interp != InterpCubic || i-2 < lo || i+1 >= hi {
+		return p1*complex(1-t, 0) + p2*complex(t, 0)
+	}
+	p0, p3 := complex128(links[i-2]), complex128(links[i+1])
+	t2, t3 := t*t, t*t*t
+	return 0.5 * (2*p1 +
+		(p2-p0)*complex(t, 0) +
+		(2*p0-5*p1+4*p2-p3)*complex(t2, 0) +
+		(3*p1-p0-3*p2+p3)*complex(t3, 0))
+}
@@ -6,12 +6,16 @@ import (
 	"image/color"
 	"image/draw"
 	"image/png"
+	"io"
 	"io/ioutil"
 	"log"
 	"math"
 	"math/cmplx"
 	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,11 +30,63 @@ import (
 
 // Constants for the Euler-Maclaurin summation
 var (
-	MinN      = 100
-	MaxN      = 65_000_000_000
-	ChunkSize = calculateDefaultChunkSize()
+	MinN = 100
+	MaxN = 65_000_000_000
+
+	// CorrectFinalLink adds the Euler-Maclaurin correction terms to the last
+	// link as well as the total. Disabling it leaves the raw partial-sum
+	// spiral without the discontinuous final jump.
+	CorrectFinalLink = true
 )
 
+// defaultChunkSize is the chunk size a ChunkConfig without one uses, fixed
+// at start-up from the CPU count and the default MaxN.
+var defaultChunkSize = calculateDefaultChunkSize()
+
+// ChunkConfig controls how the direct sum is split into chunks and how
+// they are scheduled. The zero value uses defaultChunkSize and one
+// goroutine per chunk. It is passed by value, so a caller's settings can't
+// leak into other computations.
+type ChunkConfig struct {
+	// Size is the number of terms per chunk. Below 1 uses the default.
+	Size int
+
+	// WorkStealing computes chunks on a fixed pool of runtime.NumCPU()
+	// workers that each pull the next unclaimed chunk index, instead of
+	// starting one goroutine per chunk. It keeps all cores busy when chunk
+	// costs are uneven without oversubscribing the scheduler.
+	WorkStealing bool
+
+	// Dump, when set, receives one CSV row per chunk with its term range,
+	// final partial sum and the cumulative offset it was chained onto.
+	Dump io.Writer
+
+	// Order is the order terms, and chunk sums, are accumulated in:
+	// SumAscending (or empty) or SumDescending.
+	Order string
+
+	// Terms, when positive, fixes the number of terms N for every s,
+	// bypassing the |s| heuristic and the MinN/MaxN clamp.
+	Terms int
+
+	// StartK is the first term index kept as a link; below 1 means 1.
+	// Terms below it are still summed into the total and offset every
+	// link, but are not returned, so the large early steps don't dominate
+	// the spiral's scale.
+	StartK int
+
+	// Seed, when set, is prepended to the chain before rendering as the
+	// point the first link is drawn from. The partial sums start next to
+	// 0, but with StartK or a shifted series the natural origin differs.
+	// Nil draws the chain from its own first link.
+	Seed *complex128
+
+	// Precision selects how links are stored: PrecisionFloat64 (complex128,
+	// also the empty default) or PrecisionFloat32 (complex64, half the
+	// memory). Terms are summed in float64 either way.
+	Precision string
+}
+
 // calculateDefaultChunkSize determines the chunk size based on CPU cores
 // using 1024 chunks as baseline for 20 threads (10 cores)
 func calculateDefaultChunkSize() int {
@@ -45,8 +101,9 @@ func calculateDefaultChunkSize() int {
 		targetChunks = 4096
 	}
 
-	// Calculate chunk size based on MaxN and desired chunks
-	chunkSize := (MaxN + targetChunks - 1) / targetChunks
+	// Calculate chunk size based on MaxN and desired chunks, at least one
+	// term even when MaxN is tiny.
+	chunkSize := max((MaxN+targetChunks-1)/targetChunks, 1)
 
 	log.Printf("System has %d CPU threads, using %d chunks (chunk size: %d)",
 		numThreads, targetChunks, chunkSize)
@@ -54,116 +111,367 @@ func calculateDefaultChunkSize() int {
 	return chunkSize
 }
 
+// overlayFont identifies the font used for overlay text such as tick labels.
+var overlayFont = draw2d.FontData{
+	Name:   "Arial",
+	Family: draw2d.FontFamilySans,
+	Style:  draw2d.FontStyleNormal,
+}
+
+// SetFont replaces the font used for overlay text. Tests use it to render
+// labels with a known font instead of the system one.
+func SetFont(font *truetype.Font) {
+	draw2d.RegisterFont(overlayFont, font)
+}
+
 func init() {
-	// Load the font file from macOS fonts folder.
+	// Load the font file from macOS fonts folder. Without it the image is
+	// still rendered, just without overlay text.
 	fontBytes, err := ioutil.ReadFile("/Library/Fonts/Arial Unicode.ttf")
 	if err != nil {
-		log.Fatalf("failed to read font file: %v", err)
+		log.Printf("failed to read font file, overlay text disabled: %v", err)
+		return
 	}
 
 	// Parse the font using the freetype/truetype package.
 	parsedFont, err := truetype.Parse(fontBytes)
 	if err != nil {
-		log.Fatalf("failed to parse font: %v", err)
+		log.Printf("failed to parse font, overlay text disabled: %v", err)
+		return
 	}
 
 	// Register the font so that draw2d can use it.
-	draw2d.RegisterFont(draw2d.FontData{
-		Name:   "Arial",
-		Family: draw2d.FontFamilySans,
-		Style:  draw2d.FontStyleNormal,
-	}, parsedFont)
+	SetFont(parsedFont)
 }
 
 // computePartialSumWithLinks computes the sum from [start, end) and returns
 //  1. The final partial sum for that chunk
 //  2. All intermediate partial sums in that range (the "links" for that chunk)
 func computePartialSumWithLinks(start, end int, s complex128) (complex128, []complex128) {
+	return computeSeriesWithLinks(start, end, zetaTerm(s), SumAscending)
+}
+
+// computePartialSum is computePartialSumWithLinks without the links, for
+// when only the chunk's sum is needed.
+func computePartialSum(start, end int, s complex128) complex128 {
+	return computeSeries(start, end, zetaTerm(s), SumAscending)
+}
+
+// computeSeries is computePartialSum for an arbitrary Dirichlet series whose
+// k-th term is termFn(k), accumulated in the given order.
+func computeSeries(start, end int, termFn func(k int) complex128, order string) complex128 {
+	partialSum := complex(0, 0)
+	if order == SumDescending {
+		for k := end - 1; k >= start; k-- {
+			partialSum += termFn(k)
+		}
+		return partialSum
+	}
+	for k := start; k < end; k++ {
+		partialSum += termFn(k)
+	}
+	return partialSum
+}
+
+// computeSeriesWithLinks is computePartialSumWithLinks for an arbitrary
+// Dirichlet series whose k-th term is termFn(k), accumulated in the given
+// order.
+//
+// Under SumDescending the sum runs from end-1 down, keeping the sum of the
+// terms after each k, and link k is the chunk's sum less that remainder, so
+// the last link is the descending sum exactly.
+func computeSeriesWithLinks(start, end int, termFn func(k int) complex128, order string) (complex128, []complex128) {
 	partialSum := complex(0, 0)
 	var linkList []complex128
 
+	if order == SumDescending && start < end {
+		linkList = make([]complex128, end-start)
+		for k := end - 1; k >= start; k-- {
+			// Holds the sum of the terms after k until the total is known.
+			linkList[k-start] = partialSum
+			partialSum += termFn(k)
+		}
+		for j, after := range linkList {
+			linkList[j] = partialSum - after
+		}
+		return partialSum, linkList
+	}
+
 	for k := start; k < end; k++ {
-		term := cmplx.Pow(complex(float64(k), 0), -s)
-		partialSum += term
+		partialSum += termFn(k)
 		linkList = append(linkList, partialSum)
 	}
 	return partialSum, linkList
 }
 
-// calculateSpiralPartialSums performs the multi-threaded computation and
-// returns the total sum and the properly chained links.
-func calculateSpiralPartialSums(s complex128) (complex128, []complex128) {
-	// Determine how many terms N
+// zetaTerm returns the term function k^{-s} of the ζ(s) series.
+func zetaTerm(s complex128) func(k int) complex128 {
+	return func(k int) complex128 {
+		return cmplx.Pow(complex(float64(k), 0), -s)
+	}
+}
+
+// zetaDerivativeTerm returns the term function -log(k)·k^{-s} of the ζ'(s) series.
+func zetaDerivativeTerm(s complex128) func(k int) complex128 {
+	return func(k int) complex128 {
+		fk := float64(k)
+		return complex(-math.Log(fk), 0) * cmplx.Pow(complex(fk, 0), -s)
+	}
+}
+
+// zetaTail returns the Euler-Maclaurin tail N^{1-s}/(s-1) + ½N^{-s} of the
+// ζ(s) series.
+func zetaTail(s complex128) func(N int) complex128 {
+	return func(N int) complex128 {
+		fN := complex(float64(N), 0)
+		return cmplx.Pow(fN, 1-s)/(s-1) + 0.5*cmplx.Pow(fN, -s)
+	}
+}
+
+// zetaDerivativeTail returns the derivative of zetaTail with respect to s,
+// the matching tail for the ζ'(s) series.
+func zetaDerivativeTail(s complex128) func(N int) complex128 {
+	return func(N int) complex128 {
+		fN := complex(float64(N), 0)
+		logN := complex(math.Log(float64(N)), 0)
+		pow := cmplx.Pow(fN, 1-s) / (s - 1)
+		return -logN*pow - pow/(s-1) - 0.5*logN*cmplx.Pow(fN, -s)
+	}
+}
+
+// termCount returns how many terms N to sum for s: terms if positive,
+// otherwise |s| clamped to [MinN, MaxN].
+func termCount(s complex128, terms int) int {
+	if terms > 0 {
+		return terms
+	}
 	N := int(cmplx.Abs(s))
-	println("N", N)
 	if N < MinN {
 		N = MinN
 	} else if N > MaxN {
 		N = MaxN
 	}
+	return N
+}
+
+// SpiralResult is the outcome of calculateSpiralPartialSums.
+type SpiralResult struct {
+	// Total is the corrected approximation of the series' value at s.
+	Total complex128
+	// Links are the chained partial sums, starting at StartK.
+	Links []complex128
+	// Links32 holds the links instead of Links when the ChunkConfig's
+	// Precision is float32.
+	Links32 []complex64
+	// N is the number of terms the direct sum was split at.
+	N int
+	// Correction is the Euler-Maclaurin tail (N^{1-s}/(s-1) + ½N^{-s} for ζ)
+	// added to Total (and to the last link when CorrectFinalLink is set).
+	Correction complex128
+	// Elapsed is the wall time spent computing.
+	Elapsed time.Duration
+}
+
+// calculateSpiralPartialSums performs the multi-threaded computation and
+// returns the total sum and the properly chained links.
+func calculateSpiralPartialSums(s complex128, chunks ChunkConfig) SpiralResult {
+	return calculateSeriesPartialSums(s, zetaTerm(s), zetaTail(s), chunks)
+}
+
+// calculateSpiralSum returns the same total as calculateSpiralPartialSums
+// without building any links, for callers that only want the value.
+func calculateSpiralSum(s complex128, chunks ChunkConfig) complex128 {
+	return calculateSeriesSum(s, zetaTerm(s), zetaTail(s), chunks)
+}
+
+// calculateSeriesSum is calculateSeriesPartialSums reduced to the total:
+// each chunk keeps only its running sum, so memory stays constant in N.
+func calculateSeriesSum(s complex128, termFn func(k int) complex128, tail func(N int) complex128, chunks ChunkConfig) complex128 {
+	return seriesSum(termCount(s, chunks.Terms), termFn, tail, chunks)
+}
+
+// seriesSum is calculateSeriesSum for an explicit term count N.
+func seriesSum(N int, termFn func(k int) complex128, tail func(N int) complex128, chunks ChunkConfig) complex128 {
+	chunkStarts, chunkEnds := chunkRanges(1, N, chunks)
+	partialSums := make([]complex128, len(chunkStarts))
+	runChunks(len(chunkStarts), chunks, func(idx int) {
+		partialSums[idx] = computeSeries(chunkStarts[idx], chunkEnds[idx], termFn, chunks.Order)
+	})
+
+	// Add in chunk order (or reverse, for SumDescending) so the result
+	// matches the chained links exactly.
+	var total complex128
+	if chunks.Order == SumDescending {
+		total = sumDescending(partialSums)
+	} else {
+		for _, sum := range partialSums {
+			total += sum
+		}
+	}
+	if tail != nil {
+		total += tail(N)
+	}
+	return total
+}
+
+// chunkRanges splits the terms [first, N) into chunks.Size-long [start, end)
+// ranges. The direct sum runs over k in [1, N): Euler-Maclaurin splits the
+// tail at N and its ½N^{-s} term accounts for k = N itself, so including
+// that term here would count it one and a half times.
+func chunkRanges(first, N int, chunks ChunkConfig) (starts, ends []int) {
+	size := chunks.Size
+	if size < 1 {
+		// A zero size would divide by zero below, and a negative one
+		// produce no chunks at all.
+		size = defaultChunkSize
+		if chunks.Size < 0 {
+			log.Printf("Warning: chunk size %d is negative, using the default %d", chunks.Size, size)
+		}
+	}
+
+	// Counted and bounded so that no intermediate exceeds N, which a
+	// chunk size near the int limit would otherwise overflow.
+	numChunks := 0
+	if first < N {
+		numChunks = (N-first-1)/size + 1
+	}
+	starts = make([]int, numChunks)
+	ends = make([]int, numChunks)
+	for i := 0; i < numChunks; i++ {
+		starts[i] = i*size + first
+		ends[i] = starts[i] + min(size, N-starts[i])
+	}
+	return starts, ends
+}
+
+// runChunks calls compute for every chunk index with the scheduler chosen
+// by chunks.WorkStealing.
+func runChunks(numChunks int, chunks ChunkConfig, compute func(idx int)) {
+	if chunks.WorkStealing {
+		runChunksStealing(numChunks, runtime.NumCPU(), compute)
+	} else {
+		runChunksPerGoroutine(numChunks, compute)
+	}
+}
+
+// calculateSeriesPartialSums is calculateSpiralPartialSums for an arbitrary
+// Dirichlet series with k-th term termFn(k). tail(N) returns the
+// Euler-Maclaurin estimate of the terms from k = N on; a nil tail applies
+// no correction.
+func calculateSeriesPartialSums(s complex128, termFn func(k int) complex128, tail func(N int) complex128, chunks ChunkConfig) SpiralResult {
+	return seriesPartialSums(termCount(s, chunks.Terms), termFn, tail, chunks)
+}
+
+// seriesPartialSums is calculateSeriesPartialSums for an explicit term
+// count N.
+func seriesPartialSums(N int, termFn func(k int) complex128, tail func(N int) complex128, chunks ChunkConfig) SpiralResult {
+	began := time.Now()
 	println("N", N)
 
-	// numWorkers := runtime.NumCPU()
-	// // Figure out how many chunks we need
-	// numChunks := (N + numWorkers - 1) / numWorkers
-	numChunks := 1024 // (N + ChunkSize - 1) / ChunkSize
+	// Sum the skipped prefix without keeping its links
+	first := max(chunks.StartK, 1)
+	prefix := computeSeries(1, min(first, N), termFn, chunks.Order)
+
+	chunkStarts, chunkEnds := chunkRanges(first, N, chunks)
+	numChunks := len(chunkStarts)
 
 	// Prepare slices to hold each chunk's result
 	partialSums := make([]complex128, numChunks)
 	allChunkLinks := make([][]complex128, numChunks)
-
-	var wg sync.WaitGroup
-	wg.Add(numChunks)
-
-	// Launch goroutines to compute partial sums
-	for i := 0; i < numChunks; i++ {
-		start := i*ChunkSize + 1
-		end := start + ChunkSize
-		if end > N {
-			end = N
+	allChunkLinks32 := make([][]complex64, numChunks)
+	narrow := chunks.Precision == PrecisionFloat32
+
+	// Compute partial sums
+	computeChunk := func(idx int) {
+		var links []complex128
+		partialSums[idx], links = computeSeriesWithLinks(chunkStarts[idx], chunkEnds[idx], termFn, chunks.Order)
+		if narrow {
+			// Narrow right away so the float64 copy can be freed.
+			allChunkLinks32[idx] = narrowLinks(links)
+		} else {
+			allChunkLinks[idx] = links
 		}
-
-		go func(idx, st, ed int) {
-			defer wg.Done()
-			sumVal, linkVals := computePartialSumWithLinks(st, ed, s)
-			partialSums[idx] = sumVal
-			allChunkLinks[idx] = linkVals
-		}(i, start, end)
 	}
+	runChunks(numChunks, chunks, computeChunk)
 
-	// Wait for goroutines to finish
-	wg.Wait()
+	// Each chunk's links are offset by the sum of everything before it. In
+	// ascending order that is the running sum; in descending order the
+	// chunks are added from the last, then the prefix, and each offset is
+	// that total less the chunk's own and every later chunk's sum.
+	offsets := make([]complex128, numChunks)
+	var totalSum complex128
+	if chunks.Order == SumDescending {
+		var later complex128
+		laterSums := make([]complex128, numChunks)
+		for i := numChunks - 1; i >= 0; i-- {
+			later += partialSums[i]
+			laterSums[i] = later
+		}
+		totalSum = later + prefix
+		for i := range offsets {
+			offsets[i] = totalSum - laterSums[i]
+		}
+	} else {
+		runningSum := prefix
+		for i := range offsets {
+			offsets[i] = runningSum
+			runningSum += partialSums[i]
+		}
+		// runningSum is effectively the total sum of the first N terms
+		totalSum = runningSum
+	}
 
 	// Now chain the results in the correct order
-	var totalSum complex128
 	var chainedLinks []complex128
-	runningSum := complex(0, 0)
+	var chainedLinks32 []complex64
+
+	if chunks.Dump != nil {
+		fmt.Fprintln(chunks.Dump, "chunk,start,end,partial_real,partial_imag,offset_real,offset_imag")
+	}
 
 	for i := 0; i < numChunks; i++ {
-		// Adjust this chunk's links by the runningSum so that they are continuous
+		if chunks.Dump != nil && chunkStarts[i] < chunkEnds[i] {
+			fmt.Fprintf(chunks.Dump, "%d,%d,%d,%.17g,%.17g,%.17g,%.17g\n",
+				i, chunkStarts[i], chunkEnds[i],
+				real(partialSums[i]), imag(partialSums[i]),
+				real(offsets[i]), imag(offsets[i]))
+		}
+		// Adjust this chunk's links by its offset so that they are continuous
 		for j := range allChunkLinks[i] {
-			allChunkLinks[i][j] += runningSum
+			allChunkLinks[i][j] += offsets[i]
+		}
+		for j, link := range allChunkLinks32[i] {
+			allChunkLinks32[i][j] = complex64(complex128(link) + offsets[i])
 		}
-		// Update the running sum by the chunk's final partial sum
-		runningSum += partialSums[i]
 		// Append the newly adjusted chunk links to the big list
 		chainedLinks = append(chainedLinks, allChunkLinks[i]...)
+		chainedLinks32 = append(chainedLinks32, allChunkLinks32[i]...)
 	}
 
-	// runningSum is effectively the total sum of the first N terms
-	totalSum = runningSum
-
 	// Apply Euler-Maclaurin correction terms
-	term1 := cmplx.Pow(complex(float64(N), 0), 1-s) / (s - 1)
-	term2 := 0.5 * cmplx.Pow(complex(float64(N), 0), -s)
-	totalSum += term1 + term2
+	var correction complex128
+	if tail != nil {
+		correction = tail(N)
+	}
+	totalSum += correction
 
 	// Also add corrections to the final link
-	if len(chainedLinks) > 0 {
-		chainedLinks[len(chainedLinks)-1] += term1 + term2
+	if CorrectFinalLink && len(chainedLinks) > 0 {
+		chainedLinks[len(chainedLinks)-1] += correction
+	}
+	if CorrectFinalLink && len(chainedLinks32) > 0 {
+		last := len(chainedLinks32) - 1
+		chainedLinks32[last] = complex64(complex128(chainedLinks32[last]) + correction)
 	}
 
-	return totalSum, chainedLinks
+	return SpiralResult{
+		Total:      totalSum,
+		Links:      chainedLinks,
+		Links32:    chainedLinks32,
+		N:          N,
+		Correction: correction,
+		Elapsed:    time.Since(began),
+	}
 }
 
 // calculateSingleThreadedPartialSums simply accumulates the sum link by link
@@ -180,30 +488,334 @@ func calculateSingleThreadedPartialSums(s complex128, numLinks int) []complex128
 	return links
 }
 
-// plotLinks creates and saves a PNG of the link path plus a crosshair at zeta
-func plotLinks(links []complex128, outputSize int, outputFile string, pointsOnly bool) {
-	numWorkers := runtime.NumCPU() // Number of goroutines
+// normalizeAxis maps v from the range [lo, hi] onto [0, size]. When every
+// link shares the same coordinate (lo == hi) there is no extent to scale by,
+// so the value is centered instead of producing NaN.
+func normalizeAxis(v, lo, hi float64, size int) float64 {
+	if hi == lo {
+		return float64(size) / 2
+	}
+	return (v - lo) / (hi - lo) * float64(size)
+}
 
-	// Determine the min and max for x and y across all links.
-	minX, maxX := real(links[0]), real(links[0])
-	minY, maxY := imag(links[0]), imag(links[0])
-	for _, link := range links {
-		x := real(link)
-		y := imag(link)
-		if x < minX {
-			minX = x
+// normalize maps link from the view b onto a size x size image: b's minimum
+// corner lands at (0, size) and its maximum at (size, 0), since image rows
+// run top to bottom while Y grows upwards. A degenerate axis is centered.
+func normalize(link complex128, b Bounds, size int) (fx, fy float64) {
+	fx = normalizeAxis(real(link), b.MinX, b.MaxX, size)
+	fy = float64(size) - normalizeAxis(imag(link), b.MinY, b.MaxY, size)
+	return fx, fy
+}
+
+// RenderConfig holds the options that control how links are drawn.
+type RenderConfig struct {
+	// OutputSize is the width and height of the square image in pixels.
+	OutputSize int
+	// PointsOnly draws a small dot per link instead of connecting lines.
+	PointsOnly bool
+	// Ticks draws labelled tick marks at round data-coordinate values.
+	Ticks bool
+	// ClipPercentile, when in (0, 100), frames the view on the central
+	// percentile of link coordinates so outliers don't shrink the spiral.
+	// Links outside that window are clamped to the frame edge.
+	ClipPercentile float64
+	// Dither applies ordered (Bayer) dithering when the composite is
+	// quantized to 8 bits, breaking up banding in faint gradients.
+	Dither bool
+	// Palette selects how accumulated hits are colored: PaletteWhite adds
+	// white light per hit, PaletteHeat maps hit density through a heat ramp.
+	Palette string
+	// Supersample, when greater than 1, strokes the links at Supersample
+	// times the output size and box-filters them back down for smoother
+	// edges, at the cost of Supersample² memory and fill time per worker.
+	Supersample int
+	// WidthBySpeed strokes each segment with a width proportional to its
+	// step length, so the fast early terms draw thick and the converging
+	// tail thin.
+	WidthBySpeed bool
+	// Ribbon draws the chain as a filled ribbon that tapers from
+	// maxRibbonWidth to minRibbonWidth along its length, shaded through the
+	// heat colormap from the first link to the last.
+	Ribbon bool
+	// DensityAlpha dims each segment by the density of links around it, so
+	// dense regions don't saturate to white while sparse tails keep full
+	// brightness. RenderLinksStream does not support it.
+	DensityAlpha bool
+	// ColorByResidual colors each segment by how far its link is from
+	// ResidualTarget, on a log scale through the heat colormap, to show
+	// where the partial sums converge. Its scale is fitted to the whole
+	// chain, so RenderLinksStream does not support it.
+	ColorByResidual bool
+	// ResidualTarget is the final value ColorByResidual measures against.
+	ResidualTarget complex128
+	// Fade, when positive, fades older links out for a comet-like tail:
+	// each segment's alpha decays as exp(-age/Fade), where age is the
+	// number of links between it and the last, which is drawn at full
+	// alpha. RenderLinksStream, which can't know a link's age, rejects it.
+	Fade float64
+	// Renderer selects how links are drawn: RendererDraw2D (the default)
+	// or RendererSimple. RenderLinksStream always draws with draw2d and
+	// rejects RendererSimple.
+	Renderer string
+	// Blend selects how strokes, and the worker layers holding them, are
+	// composited onto the background: BlendAdditive (the default) or
+	// BlendOver.
+	Blend string
+	// Reference overlays the unit circle and the labelled real and
+	// imaginary axes, and marks ReferencePoint if it is set.
+	Reference bool
+	// ReferencePoint is the expected convergence point Reference marks.
+	ReferencePoint *complex128
+	// MarkerSize is how far the ReferencePoint marker reaches from its
+	// centre, in output pixels; 0 scales it with OutputSize.
+	MarkerSize float64
+	// MarkerStyle is the marker's shape: MarkerX (the default),
+	// MarkerPlus or MarkerCircle.
+	MarkerStyle string
+	// MarkerColor colors the marker; the zero value keeps the default
+	// orange.
+	MarkerColor color.RGBA
+	// Quiver, when positive, draws an arrow along the step to the next link
+	// at every Quiver-th link, showing how the phase rotates along the
+	// spiral. RendererSimple leaves it out and RenderLinksStream rejects it.
+	Quiver int
+	// Mirror also draws the complex conjugate of every link, reflecting
+	// the spiral across the real axis: the spiral for conj(s), since
+	// ζ(conj s) = conj ζ(s). The view is widened to keep it symmetric unless
+	// Bounds is set. RenderLinksStream does not support it.
+	Mirror bool
+	// Bounds, when set, fixes the view to this data-coordinate rectangle
+	// instead of fitting it to the links. Links outside it are clamped to
+	// the frame edge. RenderLinksStream requires it.
+	Bounds *Bounds
+	// ClipSegments, with Bounds set, clips segments where they cross the
+	// frame edge and leaves out links beyond it, rather than clamping them
+	// to the edge. RenderLinksStream does not support it.
+	ClipSegments bool
+}
+
+// Line widths used by RenderConfig.WidthBySpeed for the shortest and the
+// longest step, in output pixels.
+const (
+	minSpeedWidth = 0.5
+	maxSpeedWidth = 6.0
+)
+
+// Ribbon widths at the first and last link, in output pixels.
+const (
+	maxRibbonWidth = 8.0
+	minRibbonWidth = 1.0
+)
+
+// ribbonWidth returns the ribbon width at link i of n, tapering linearly.
+func ribbonWidth(i, n int) float64 {
+	if n < 2 {
+		return maxRibbonWidth
+	}
+	return maxRibbonWidth - (maxRibbonWidth-minRibbonWidth)*float64(i)/float64(n-1)
+}
+
+// ribbonColor returns the fill for the segment ending at link i of n: the
+// heat ramp from white at the start through yellow and red to dark red at
+// the tail, premultiplied at half opacity.
+func ribbonColor(i, n int) color.RGBA {
+	c := heatColor(1 - 0.8*float64(i)/float64(max(n-1, 1)))
+	return color.RGBA{c.R / 2, c.G / 2, c.B / 2, 128}
+}
+
+// fillRibbonSegment fills the quad between (x0, y0) and (x1, y1), offset
+// either side along the segment normal by half of w0 and w1 respectively.
+func fillRibbonSegment(gc *draw2dimg.GraphicContext, x0, y0, x1, y1, w0, w1 float64) {
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	nx, ny := -dy/length, dx/length
+	gc.BeginPath()
+	gc.MoveTo(x0+nx*w0/2, y0+ny*w0/2)
+	gc.LineTo(x1+nx*w1/2, y1+ny*w1/2)
+	gc.LineTo(x1-nx*w1/2, y1-ny*w1/2)
+	gc.LineTo(x0-nx*w0/2, y0-ny*w0/2)
+	gc.Close()
+	gc.Fill()
+}
+
+// speedWidth maps a step length to a line width between minSpeedWidth and
+// maxSpeedWidth in proportion to maxStep.
+func speedWidth(step, maxStep float64) float64 {
+	if maxStep <= 0 {
+		return minSpeedWidth
+	}
+	return minSpeedWidth + (maxSpeedWidth-minSpeedWidth)*step/maxStep
+}
+
+// bayer4 is the 4x4 ordered-dither threshold matrix.
+var bayer4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// quantize rounds a channel value to 8 bits, clamping to [0, 255]. With dither
+// set, a deterministic position-dependent threshold in (-0.5, 0.5) is added
+// first so fractional levels become a fine pattern of neighbouring values
+// instead of flat bands.
+func quantize(v float64, x, y int, dither bool) uint8 {
+	if dither {
+		v += (bayer4[y&3][x&3]+0.5)/16 - 0.5
+	}
+	return uint8(math.Min(math.Max(math.Round(v), 0), 255))
+}
+
+// boxDownsample shrinks img by an integer factor, averaging each
+// factor x factor block of premultiplied pixels into one output pixel.
+func boxDownsample(img *image.RGBA, factor int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx()/factor, b.Dy()/factor))
+	area := float64(factor * factor)
+	for y := 0; y < out.Rect.Dy(); y++ {
+		for x := 0; x < out.Rect.Dx(); x++ {
+			var sum [4]float64
+			for sy := 0; sy < factor; sy++ {
+				row := (y*factor+sy)*img.Stride + x*factor*4
+				for sx := 0; sx < factor; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += float64(img.Pix[row+sx*4+c])
+					}
+				}
+			}
+			offset := y*out.Stride + x*4
+			for c := 0; c < 4; c++ {
+				out.Pix[offset+c] = uint8(math.Round(sum[c] / area))
+			}
 		}
-		if x > maxX {
-			maxX = x
+	}
+	return out
+}
+
+// thumbnail box-filters img down to size x size, averaging the block of
+// source pixels that maps onto each output pixel. Unlike boxDownsample the
+// factor need not be an integer; blocks then differ in size by one pixel.
+func thumbnail(img *image.RGBA, size int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := y*b.Dy()/size, (y+1)*b.Dy()/size
+		for x := 0; x < size; x++ {
+			x0, x1 := x*b.Dx()/size, (x+1)*b.Dx()/size
+			var sum [4]float64
+			for sy := y0; sy < y1; sy++ {
+				row := sy * img.Stride
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += float64(img.Pix[row+sx*4+c])
+					}
+				}
+			}
+			area := float64((y1 - y0) * (x1 - x0))
+			offset := y*out.Stride + x*4
+			for c := 0; c < 4; c++ {
+				out.Pix[offset+c] = uint8(math.Round(sum[c] / area))
+			}
 		}
-		if y < minY {
-			minY = y
+	}
+	return out
+}
+
+// plotLinks creates and saves a PNG of the link path plus a crosshair at zeta
+func plotLinks(links []complex128, cfg RenderConfig, outputFile string) *image.RGBA {
+	finalImage := renderLinks(links, cfg)
+
+	// Save the final image.
+	if err := savePNG(finalImage, outputFile); err != nil {
+		log.Fatalf("failed to save image: %v", err)
+	}
+
+	log.Println("Image saved as", outputFile)
+	return finalImage
+}
+
+// savePNG encodes img as a PNG file.
+func savePNG(img image.Image, filename string) error {
+	outFile, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	if err := png.Encode(outFile, img); err != nil {
+		return err
+	}
+	return outFile.Close()
+}
+
+// renderWorkers is how many goroutines renderLinks splits the links among.
+var renderWorkers = runtime.NumCPU()
+
+// renderLinks draws the link path into a new OutputSize x OutputSize image
+// using renderWorkers goroutines and additively composites the results.
+// Workers left without links draw no layer, leaving theirs nil.
+func renderLinks(links []complex128, cfg RenderConfig) *image.RGBA {
+	if cfg.Renderer == RendererSimple {
+		return renderLinksSimple(links, cfg)
+	}
+
+	numWorkers := renderWorkers // Number of goroutines
+	outputSize := cfg.OutputSize
+	pointsOnly := cfg.PointsOnly
+	scale := max(cfg.Supersample, 1)
+	drawSize := outputSize * scale
+
+	view, clip := viewBounds(links, cfg)
+	// Clipped segments are framed unclamped and cut at the edge instead.
+	clipSegments := cfg.ClipSegments && cfg.Bounds != nil
+	if clipSegments {
+		clip = false
+	}
+
+	// The mirror chain is drawn by the same workers as the links, each
+	// stroking the conjugates of its own share.
+	chains := [][]complex128{links}
+	if cfg.Mirror {
+		chains = append(chains, mirrorLinks(links))
+		if cfg.Bounds == nil {
+			view = mirrorView(view)
 		}
-		if y > maxY {
-			maxY = y
+	}
+
+	ribbon := cfg.Ribbon && !pointsOnly
+
+	// Line widths scale against the largest step in the chain.
+	widthBySpeed := cfg.WidthBySpeed && !pointsOnly && !ribbon
+	var maxStep float64
+	if widthBySpeed {
+		for i := 1; i < len(links); i++ {
+			maxStep = math.Max(maxStep, cmplx.Abs(links[i]-links[i-1]))
 		}
 	}
-	log.Printf("Link X range: [%f, %f], Y range: [%f, %f]\n", minX, maxX, minY, maxY)
+
+	// Segment brightness follows the link density around it.
+	var density *densityGrid
+	if cfg.DensityAlpha && !pointsOnly && !ribbon {
+		density = newDensityGrid(chains, view, outputSize, clip)
+	}
+	// Segment color follows the distance from the final value.
+	var residual *residualScale
+	if cfg.ColorByResidual && !pointsOnly && !ribbon {
+		residual = newResidualScale(links, cfg.ResidualTarget)
+	}
+	// Segment alpha follows the link's age.
+	fadeTau := 0.0
+	if cfg.Fade > 0 && !pointsOnly && !ribbon {
+		fadeTau = cfg.Fade
+	}
+	// The heat palette colors pixels by how often strokes cross them.
+	var hits *hitBuffer
+	if cfg.Palette == PaletteHeat {
+		hits = newHitBuffer(outputSize, scale)
+	}
 
 	// Divide the links among workers.
 	chunkSize := (len(links) + numWorkers - 1) / numWorkers
@@ -217,58 +829,91 @@ func plotLinks(links []complex128, outputSize int, outputFile string, pointsOnly
 		if end > len(links) {
 			end = len(links)
 		}
+		if end <= start {
+			// Rounding the chunk size up can leave the last workers with
+			// nothing to draw; they allocate no layer.
+			log.Printf("Worker %d has no links to draw\n", i)
+			continue
+		}
 		wg.Add(1)
 		go func(worker, start, end int) {
 			defer wg.Done()
 			log.Printf("Worker %d drawing links from index %d to %d\n", worker, start, end)
 			// Create full-size image with transparent background.
-			img := image.NewRGBA(image.Rect(0, 0, outputSize, outputSize))
-			// Clear image to transparent.
-			gc := draw2dimg.NewGraphicContext(img)
-			gc.SetFillColor(color.RGBA{0, 0, 0, 0})
-			gc.Clear()
-
-			// Set drawn line properties in white with higher base opacity
-			if pointsOnly {
-				gc.SetStrokeColor(color.RGBA{255, 255, 255, 255})
-				gc.SetFillColor(color.RGBA{255, 255, 255, 255})
-			} else {
-				// Use higher base opacity (128 instead of 64) for better line accumulation
-				gc.SetStrokeColor(color.RGBA{255, 255, 255, 128})
-			}
-			gc.SetLineWidth(0.5)
-
-			// Draw the links in this chunk.
-			if end > start {
+			img := image.NewRGBA(image.Rect(0, 0, drawSize, drawSize))
+			painter := newLayerPainter(img, cfg.Blend, hits)
+			gc := draw2dimg.NewGraphicContextWithPainter(img, painter)
+			strokes := newSegmentStroker(painter, drawSize)
+
+			// Points are drawn in opaque white; lines use a higher base
+			// opacity (128 instead of 64) for better line accumulation.
+			gc.SetStrokeColor(color.RGBA{255, 255, 255, 255})
+			gc.SetFillColor(color.RGBA{255, 255, 255, 255})
+			gc.SetLineWidth(0.5 * float64(scale))
+
+			// Draw the links in this chunk, each worker also drawing the
+			// segment that leads into its first link so the chain has no
+			// gaps where chunks meet. Width, color and alpha depend only on
+			// |links[j]-links[j-1]| and |links[j]-target|, so the mirror
+			// chain shares them with the links.
+			for _, chain := range chains {
+				var prevX, prevY float64
+				if start > 0 {
+					prevX, prevY = framePoint(chain[start-1], view, drawSize, clip)
+				}
 				for j := start; j < end; j++ {
-					x := real(links[j])
-					y := imag(links[j])
-					// Normalize x and y into [0, outputSize] based on overall range.
-					normalizedX := (x - minX) / (maxX - minX) * float64(outputSize)
-					normalizedY := (y - minY) / (maxY - minY) * float64(outputSize)
-					// Invert Y because image coordinates start at top.
-					finalX := normalizedX
-					finalY := float64(outputSize) - normalizedY
+					finalX, finalY := framePoint(chain[j], view, drawSize, clip)
+					// The segment from the previous link, cut at the frame
+					// edge when clipping.
+					x0, y0, x1, y1, visible := prevX, prevY, finalX, finalY, j > 0
+					if visible && clipSegments {
+						x0, y0, x1, y1, visible = clipSegment(prevX, prevY, finalX, finalY, float64(drawSize))
+					}
+					prevX, prevY = finalX, finalY
 
 					if pointsOnly {
+						if clipSegments && !inFrame(finalX, finalY, float64(drawSize)) {
+							continue
+						}
 						// Draw a small circle for each point
+						radius := float64(scale)
 						gc.BeginPath()
-						gc.ArcTo(finalX, finalY, 1.0, 1.0, 0, 2*math.Pi)
+						gc.ArcTo(finalX, finalY, radius, radius, 0, 2*math.Pi)
 						gc.Close()
 						gc.FillStroke()
-					} else {
-						if j == start {
-							gc.MoveTo(finalX, finalY)
-						} else {
-							gc.LineTo(finalX, finalY)
+					} else if ribbon {
+						// Fill a quad per segment, tapering and shading along the chain.
+						if visible {
+							w0 := ribbonWidth(j-1, len(links)) * float64(scale)
+							w1 := ribbonWidth(j, len(links)) * float64(scale)
+							gc.SetFillColor(ribbonColor(j, len(links)))
+							fillRibbonSegment(gc, x0, y0, x1, y1, w0, w1)
 						}
+					} else if visible {
+						// Stroke each segment on its own, so every pass over a
+						// pixel counts where the chain crosses itself, its width
+						// can follow the step size, its color the residual and
+						// local density, and its alpha the link's age.
+						width := 0.5 * float64(scale)
+						if widthBySpeed {
+							width = speedWidth(cmplx.Abs(links[j]-links[j-1]), maxStep) * float64(scale)
+						}
+						stroke := color.RGBA{255, 255, 255, 128}
+						if residual != nil {
+							stroke = residual.color(links[j])
+						}
+						if density != nil {
+							stroke = density.dim(stroke, x0/float64(scale), y0/float64(scale), x1/float64(scale), y1/float64(scale))
+						}
+						if fadeTau > 0 {
+							stroke = fade(stroke, j, len(links), fadeTau)
+						}
+						strokes.stroke(x0, y0, x1, y1, width, stroke)
 					}
 				}
-				if !pointsOnly {
-					gc.Stroke()
-				}
-			} else {
-				log.Printf("Worker %d has no links to draw\n", worker)
+			}
+			if scale > 1 {
+				img = boxDownsample(img, scale)
 			}
 			workerImages[worker] = img
 		}(i, start, end)
@@ -276,21 +921,59 @@ func plotLinks(links []complex128, outputSize int, outputFile string, pointsOnly
 	wg.Wait()
 	log.Println("All workers completed processing their chunks.")
 
+	finalImage := compositeLayers(workerImages, hits, cfg, view)
+	if cfg.Quiver > 0 {
+		drawQuiver(finalImage, links, view, clip, cfg.Quiver)
+	}
+	return finalImage
+}
+
+// viewBounds returns the data-coordinate view for links under cfg: the
+// supplied Bounds, the ClipPercentile window, or else the links' full
+// extent. clip reports whether links can fall outside the view and must be
+// clamped to its edge.
+func viewBounds(links []complex128, cfg RenderConfig) (view Bounds, clip bool) {
+	// Determine the min and max for x and y across all links.
+	view = boundsOf(links)
+	log.Printf("Link X range: [%f, %f], Y range: [%f, %f]\n", view.MinX, view.MaxX, view.MinY, view.MaxY)
+
+	clip = cfg.ClipPercentile > 0 && cfg.ClipPercentile < 100
+	switch {
+	case cfg.Bounds != nil:
+		view = *cfg.Bounds
+		clip = true
+		log.Printf("Using supplied X range: [%f, %f], Y range: [%f, %f]\n", view.MinX, view.MaxX, view.MinY, view.MaxY)
+	case clip:
+		view = percentileBounds(links, cfg.ClipPercentile)
+		log.Printf("Clipped to %.3f%% X range: [%f, %f], Y range: [%f, %f]\n",
+			cfg.ClipPercentile, view.MinX, view.MaxX, view.MinY, view.MaxY)
+	}
+	return view, clip
+}
+
+// framePoint is normalize, with points outside the frame pinned to its
+// edge when clamp is set.
+func framePoint(link complex128, view Bounds, size int, clamp bool) (x, y float64) {
+	x, y = normalize(link, view, size)
+	if clamp {
+		x = math.Min(math.Max(x, 0), float64(size))
+		y = math.Min(math.Max(y, 0), float64(size))
+	}
+	return x, y
+}
+
+// blendLayers additively blends the transparent per-worker layers onto the
+// dark background, skipping nil ones, and applies cfg's palette, coloring
+// pixels by their hits under the heat palette, and dithering.
+func blendLayers(workerImages []*image.RGBA, hits *hitBuffer, cfg RenderConfig) *image.RGBA {
+	outputSize := cfg.OutputSize
+
 	// Create the base final image with a solid dark grey background.
 	finalImage := image.NewRGBA(image.Rect(0, 0, outputSize, outputSize))
 	draw.Draw(finalImage, finalImage.Bounds(), &image.Uniform{color.RGBA{30, 30, 30, 255}}, image.Point{}, draw.Src)
 
-	// Custom compositing function for additive blending
-	additive := func(dst, src color.RGBA) color.RGBA {
-		// Add the color values, clamping at 255
-		r := uint8(math.Min(float64(dst.R)+float64(src.R), 255))
-		g := uint8(math.Min(float64(dst.G)+float64(src.G), 255))
-		b := uint8(math.Min(float64(dst.B)+float64(src.B), 255))
-		a := uint8(math.Min(float64(dst.A)+float64(src.A), 255))
-		return color.RGBA{r, g, b, a}
-	}
-
-	// Composite each worker's transparent image using parallel additive blending
+	// Composite each worker's transparent image in parallel, additively or
+	// with over as cfg.Blend selects
 	bounds := finalImage.Bounds()
 	height := bounds.Dy()
 	width := bounds.Dx()
@@ -311,42 +994,51 @@ func plotLinks(links []complex128, outputSize int, outputFile string, pointsOnly
 		go func(startY, endY int) {
 			defer compositeWg.Done()
 
-			// Pre-calculate pixel offsets for the row
 			for y := startY; y < endY; y++ {
 				baseOffset := y * finalImage.Stride
 
-				// Process each worker image
-				for _, img := range workerImages {
-					imgPixels := img.Pix
-					for x := 0; x < width; x++ {
-						offset := baseOffset + x*4
+				for x := 0; x < width; x++ {
+					offset := baseOffset + x*4
 
+					// Accumulate at full precision, starting from the background,
+					// and only clamp and quantize once all layers are summed.
+					var acc [4]float64
+					for c := 0; c < 4; c++ {
+						acc[c] = float64(finalImage.Pix[offset+c])
+					}
+					for _, img := range workerImages {
+						// Workers without links leave no layer.
+						if img == nil {
+							continue
+						}
+						imgPixels := img.Pix
 						// Skip if source pixel is fully transparent
 						if imgPixels[offset+3] == 0 {
 							continue
 						}
-
-						// Direct pixel access for better performance
-						src := color.RGBA{
-							imgPixels[offset+0],
-							imgPixels[offset+1],
-							imgPixels[offset+2],
-							imgPixels[offset+3],
+						if cfg.Blend == BlendOver {
+							// Premultiplied over: the layer covers its alpha's
+							// share of what is beneath.
+							cover := 1 - float64(imgPixels[offset+3])/255
+							for c := 0; c < 4; c++ {
+								acc[c] = float64(imgPixels[offset+c]) + acc[c]*cover
+							}
+						} else {
+							for c := 0; c < 4; c++ {
+								acc[c] += float64(imgPixels[offset+c])
+							}
 						}
+					}
 
-						dst := color.RGBA{
-							finalImage.Pix[offset+0],
-							finalImage.Pix[offset+1],
-							finalImage.Pix[offset+2],
-							finalImage.Pix[offset+3],
+					if hits != nil {
+						if density := hits.density(x, y); density > 0 {
+							heat := densityColor(density)
+							acc = [4]float64{float64(heat.R), float64(heat.G), float64(heat.B), 255}
 						}
+					}
 
-						result := additive(dst, src)
-
-						finalImage.Pix[offset+0] = result.R
-						finalImage.Pix[offset+1] = result.G
-						finalImage.Pix[offset+2] = result.B
-						finalImage.Pix[offset+3] = result.A
+					for c := 0; c < 4; c++ {
+						finalImage.Pix[offset+c] = quantize(acc[c], x, y, cfg.Dither)
 					}
 				}
 			}
@@ -355,6 +1047,14 @@ func plotLinks(links []complex128, outputSize int, outputFile string, pointsOnly
 
 	compositeWg.Wait()
 	log.Println("Compositing complete")
+	return finalImage
+}
+
+// compositeLayers blends the per-worker layers and hits with blendLayers
+// and draws the axis and tick overlay for view.
+func compositeLayers(workerImages []*image.RGBA, hits *hitBuffer, cfg RenderConfig, view Bounds) *image.RGBA {
+	outputSize := cfg.OutputSize
+	finalImage := blendLayers(workerImages, hits, cfg)
 
 	// Create an overlay layer for axis markers and text (drawn in white).
 	overlay := image.NewRGBA(image.Rect(0, 0, outputSize, outputSize))
@@ -366,18 +1066,13 @@ func plotLinks(links []complex128, outputSize int, outputFile string, pointsOnly
 	gcOverlay.SetStrokeColor(color.White)
 	gcOverlay.SetFillColor(color.White)
 	gcOverlay.SetLineWidth(2)
-	gcOverlay.SetFontData(draw2d.FontData{
-		Name:   "Arial",
-		Family: draw2d.FontFamilySans,
-		Style:  draw2d.FontStyleNormal,
-	})
+	gcOverlay.SetFontData(overlayFont)
 	gcOverlay.SetFontSize(14)
 
-	// Draw simple axis markers:
+	// Draw simple axis markers through the origin.
+	x0, y0 := normalize(0, view, outputSize)
 	// X-axis: if 0 is in the y-range, draw a horizontal line.
-	if minY <= 0 && maxY >= 0 {
-		normalizedY := (0 - minY) / (maxY - minY) * float64(outputSize)
-		y0 := float64(outputSize) - normalizedY
+	if view.MinY <= 0 && view.MaxY >= 0 {
 		gcOverlay.SetLineWidth(1)
 		gcOverlay.SetStrokeColor(color.RGBA{30, 30, 30, 66})
 		gcOverlay.MoveTo(0, y0)
@@ -385,32 +1080,133 @@ func plotLinks(links []complex128, outputSize int, outputFile string, pointsOnly
 		gcOverlay.Stroke()
 	}
 	// Y-axis: if 0 is in the x-range, draw a vertical line.
-	if minX <= 0 && maxX >= 0 {
-		normalizedX := (0 - minX) / (maxX - minX) * float64(outputSize)
+	if view.MinX <= 0 && view.MaxX >= 0 {
 		gcOverlay.SetLineWidth(1)
 		gcOverlay.SetStrokeColor(color.RGBA{30, 30, 30, 66})
-		gcOverlay.MoveTo(normalizedX, 0)
-		gcOverlay.LineTo(normalizedX, float64(outputSize))
+		gcOverlay.MoveTo(x0, 0)
+		gcOverlay.LineTo(x0, float64(outputSize))
 		gcOverlay.Stroke()
 	}
 
+	if cfg.Ticks {
+		drawTicks(gcOverlay, view, outputSize)
+	}
+	if cfg.Reference {
+		drawReference(gcOverlay, view, outputSize, cfg)
+	}
+
 	// Composite the overlay onto the final image.
 	draw.Draw(finalImage, finalImage.Bounds(), overlay, image.Point{}, draw.Over)
 
 	log.Printf("Final image dimensions: %dx%d\n", finalImage.Bounds().Dx(), finalImage.Bounds().Dy())
 
-	// Save the final image.
-	outFile, err := os.Create(outputFile)
-	if err != nil {
-		log.Fatalf("failed to create output file: %v", err)
+	return finalImage
+}
+
+// percentileBounds returns bounds covering the central pct percent of link
+// coordinates on each axis, trimming (100-pct)/2 percent from either end.
+func percentileBounds(links []complex128, pct float64) Bounds {
+	xs := make([]float64, len(links))
+	ys := make([]float64, len(links))
+	for i, link := range links {
+		xs[i] = real(link)
+		ys[i] = imag(link)
 	}
-	defer outFile.Close()
+	sort.Float64s(xs)
+	sort.Float64s(ys)
 
-	if err := png.Encode(outFile, finalImage); err != nil {
-		log.Fatalf("failed to encode image: %v", err)
+	tail := (100 - pct) / 200
+	lo := int(math.Floor(tail * float64(len(links)-1)))
+	hi := int(math.Ceil((1 - tail) * float64(len(links)-1)))
+	return Bounds{xs[lo], xs[hi], ys[lo], ys[hi]}
+}
+
+// niceTickStep picks a round spacing (1, 2 or 5 times a power of ten) that
+// splits span into roughly targetTicks intervals.
+func niceTickStep(span float64, targetTicks int) float64 {
+	if span <= 0 || targetTicks < 1 {
+		return 0
+	}
+	raw := span / float64(targetTicks)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	fraction := raw / magnitude
+
+	var nice float64
+	switch {
+	case fraction < 1.5:
+		nice = 1
+	case fraction < 3:
+		nice = 2
+	case fraction < 7:
+		nice = 5
+	default:
+		nice = 10
 	}
+	return nice * magnitude
+}
 
-	log.Println("Image saved as", outputFile)
+// tickValues returns the multiples of step that fall within [lo, hi].
+func tickValues(lo, hi, step float64) []float64 {
+	if step <= 0 {
+		return nil
+	}
+	var values []float64
+	for k := math.Ceil(lo / step); k*step <= hi; k++ {
+		values = append(values, k*step)
+	}
+	return values
+}
+
+// tickLabel formats v with just enough decimals to distinguish ticks step apart.
+func tickLabel(v, step float64) string {
+	decimals := int(math.Max(0, -math.Floor(math.Log10(step))))
+	if v == 0 {
+		v = 0 // avoid printing "-0.0"
+	}
+	return fmt.Sprintf("%.*f", decimals, v)
+}
+
+// drawTicks draws labelled tick marks along the bottom (real axis) and left
+// (imaginary axis) edges of the image at round data-coordinate values.
+func drawTicks(gc *draw2dimg.GraphicContext, view Bounds, outputSize int) {
+	const targetTicks = 5
+	const tickLength = 6.0
+	size := float64(outputSize)
+
+	gc.SetLineWidth(1)
+	gc.SetStrokeColor(color.White)
+	gc.SetFillColor(color.White)
+
+	if view.MaxX > view.MinX {
+		step := niceTickStep(view.Width(), targetTicks)
+		for _, v := range tickValues(view.MinX, view.MaxX, step) {
+			x := normalizeAxis(v, view.MinX, view.MaxX, outputSize)
+			gc.MoveTo(x, size)
+			gc.LineTo(x, size-tickLength)
+			gc.Stroke()
+
+			label := tickLabel(v, step)
+			left, _, right, _ := gc.GetStringBounds(label)
+			// Keep labels at the edges inside the frame.
+			lx := math.Min(math.Max(x-(right-left)/2, 0), size-(right-left))
+			gc.FillStringAt(label, lx, size-tickLength-4)
+		}
+	}
+
+	if view.MaxY > view.MinY {
+		step := niceTickStep(view.Height(), targetTicks)
+		for _, v := range tickValues(view.MinY, view.MaxY, step) {
+			y := size - normalizeAxis(v, view.MinY, view.MaxY, outputSize)
+			gc.MoveTo(0, y)
+			gc.LineTo(tickLength, y)
+			gc.Stroke()
+
+			label := tickLabel(v, step)
+			_, top, _, bottom := gc.GetStringBounds(label)
+			ly := math.Min(math.Max(y+(bottom-top)/2, bottom-top), size)
+			gc.FillStringAt(label, tickLength+4, ly)
+		}
+	}
 }
 
 // Point represents a 2D point.
@@ -440,11 +1236,51 @@ func downsample(points []Point, groupSize int) []Point {
 	return result
 }
 
+// Supported downsampling aggressiveness range. 0 merges only links that share
+// a pixel; each step up widens the grouping and interpolation thresholds
+// geometrically.
+const (
+	MinAggressiveness = 0.0
+	MaxAggressiveness = 4.0
+)
+
+// aggressivenessRampStart is where the geometric thresholds give way to a
+// linear ramp toward fixed caps, so the top of the range stays usable instead
+// of collapsing the spiral to a handful of points.
+const aggressivenessRampStart = 3.5
+
+// clampAggressiveness limits aggressiveness to the supported range, logging
+// a warning when the requested value had to be adjusted.
+func clampAggressiveness(aggressiveness float64) float64 {
+	clamped := math.Min(math.Max(aggressiveness, MinAggressiveness), MaxAggressiveness)
+	if clamped != aggressiveness {
+		log.Printf("Warning: aggressiveness %.2f outside supported range [%.1f, %.1f], using %.1f",
+			aggressiveness, MinAggressiveness, MaxAggressiveness, clamped)
+	}
+	return clamped
+}
+
+// DownsampleOptions controls how the downsampler fills the gaps between
+// groups and whether it keeps the chain's bounds. The zero value fills
+// gaps linearly and lets averaging pull the bounds in. It is passed by
+// value, like ChunkConfig.
+type DownsampleOptions struct {
+	// Interpolation selects how gaps are filled: InterpLinear (or empty)
+	// or InterpCubic.
+	Interpolation string
+
+	// PreserveExtremes keeps the links at the minimum and maximum X and Y
+	// unmodified, so the downsampled chain has exactly the bounds of the
+	// original instead of being pulled inward by averaging.
+	PreserveExtremes bool
+}
+
 // downsampleComplexSerial is the original serial version of the downsampling algorithm
-func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness float64, debug bool) []complex128 {
+func downsampleComplexSerial[T linkValue](links []T, outputSize int, aggressiveness float64, opts DownsampleOptions, debug bool) []complex128 {
 	if len(links) == 0 {
-		return links
+		return nil
 	}
+	aggressiveness = clampAggressiveness(aggressiveness)
 
 	if debug {
 		log.Printf("Starting downsampleComplexSerial with %d links and output size %d (aggressiveness: %.2f)",
@@ -452,27 +1288,10 @@ func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness
 	}
 
 	// Determine view bounds from the links.
-	minX, maxX := real(links[0]), real(links[0])
-	minY, maxY := imag(links[0]), imag(links[0])
-	for _, link := range links {
-		x := real(link)
-		y := imag(link)
-		if x < minX {
-			minX = x
-		}
-		if x > maxX {
-			maxX = x
-		}
-		if y < minY {
-			minY = y
-		}
-		if y > maxY {
-			maxY = y
-		}
-	}
+	view := boundsOf(links)
 
 	// Calculate relative distance between points
-	maxRange := math.Max(maxX-minX, maxY-minY)
+	maxRange := math.Max(view.Width(), view.Height())
 	baseRange := math.Max(0.01, maxRange)
 	relativeSpread := maxRange / baseRange
 
@@ -482,9 +1301,9 @@ func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness
 		maxRelativeSpread *= math.Pow(5, aggressiveness)
 	}
 
-	// Add extra smoothing for values between 3.5 and 4.0
-	if aggressiveness > 3.5 {
-		t := (aggressiveness - 3.5) / 0.5
+	// Add extra smoothing for values between the ramp start and the maximum
+	if aggressiveness > aggressivenessRampStart {
+		t := (aggressiveness - aggressivenessRampStart) / (MaxAggressiveness - aggressivenessRampStart)
 		maxRelativeSpread = 0.03 + (0.02 * t)
 	}
 
@@ -498,25 +1317,24 @@ func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness
 	if relativeSpread <= maxRelativeSpread {
 		var sum complex128
 		for _, link := range links {
-			sum += link
+			sum += complex128(link)
 		}
 		avg := sum / complex(float64(len(links)), 0)
-		return []complex128{avg}
+		return preserveExtremes(links, []complex128{avg}, opts)
 	}
 
 	// Helper to compute pixel coordinate for a link
 	pixelForLink := func(link complex128) (int, int) {
-		normalizedX := (real(link) - minX) / (maxX - minX)
-		normalizedY := (imag(link) - minY) / (maxY - minY)
-		px := int(math.Round(normalizedX * float64(outputSize)))
-		py := int(math.Round(normalizedY * float64(outputSize)))
+		fx, fy := normalize(link, view, outputSize)
+		px := int(math.Round(fx))
+		py := int(math.Round(fy))
 		return px, py
 	}
 
 	// Calculate interpolation threshold based on aggressiveness
 	interpolationThreshold := 1.1 * math.Pow(2.5, aggressiveness)
-	if aggressiveness > 3.5 {
-		t := (aggressiveness - 3.5) / 0.5
+	if aggressiveness > aggressivenessRampStart {
+		t := (aggressiveness - aggressivenessRampStart) / (MaxAggressiveness - aggressivenessRampStart)
 		interpolationThreshold = 55.0 + (20.0 * t)
 	}
 
@@ -530,13 +1348,13 @@ func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness
 	}
 
 	// Initialize with first point
-	initPx, initPy := pixelForLink(links[0])
+	initPx, initPy := pixelForLink(complex128(links[0]))
 	currentGroup := groupData{
-		sum:      links[0],
+		sum:      complex128(links[0]),
 		count:    1,
 		pixelX:   initPx,
 		pixelY:   initPy,
-		lastLink: links[0],
+		lastLink: complex128(links[0]),
 	}
 
 	// Helper to flush a group
@@ -546,7 +1364,7 @@ func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness
 
 	// Process all points sequentially
 	for i := 1; i < len(links); i++ {
-		link := links[i]
+		link := complex128(links[i])
 		px, py := pixelForLink(link)
 
 		// Check if this point belongs to current group
@@ -569,16 +1387,15 @@ func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness
 		pixelGap := math.Sqrt(float64(dx*dx + dy*dy))
 
 		if pixelGap > interpolationThreshold {
-			steps := int(pixelGap / math.Pow(2, math.Min(aggressiveness, 3.5)))
-			if aggressiveness > 3.5 {
-				t := (aggressiveness - 3.5) / 0.5
+			steps := int(pixelGap / math.Pow(2, math.Min(aggressiveness, aggressivenessRampStart)))
+			if aggressiveness > aggressivenessRampStart {
+				t := (aggressiveness - aggressivenessRampStart) / (MaxAggressiveness - aggressivenessRampStart)
 				steps = int(float64(steps) * (1.0 - (0.5 * t)))
 			}
 
 			for s := 1; s <= steps; s++ {
 				t := float64(s) / float64(steps+1)
-				interp := currentGroup.lastLink*(1-complex(t, 0)) + link*complex(t, 0)
-				downsampled = append(downsampled, interp)
+				downsampled = append(downsampled, gapPoint(links, 0, len(links), i, t, opts.Interpolation))
 			}
 		}
 
@@ -599,50 +1416,41 @@ func downsampleComplexSerial(links []complex128, outputSize int, aggressiveness
 	if debug {
 		log.Printf("Downsampled %d points to %d points", len(links), len(downsampled))
 	}
-	return downsampled
+	return preserveExtremes(links, downsampled, opts)
 }
 
 // downsampleComplex uses the view bounds (computed from all links) and the output image size,
 // so that only links that fall within the same pixel are averaged. Additionally, if two adjacent
-// groups are separated by more than one pixel, it linearly interpolates extra points.
-// aggressiveness controls how much reduction to do (0.0 = minimal, 1.0 = maximum)
-func downsampleComplex(links []complex128, outputSize int, aggressiveness float64, debug bool) []complex128 {
+// groups are separated by more than one pixel, it interpolates extra points as opts.Interpolation selects.
+// aggressiveness controls how much reduction to do, from MinAggressiveness
+// (minimal) to MaxAggressiveness (maximum); values outside are clamped.
+func downsampleComplex[T linkValue](links []T, outputSize int, aggressiveness float64, opts DownsampleOptions, debug bool) []complex128 {
+	aggressiveness = clampAggressiveness(aggressiveness)
 
 	// There is not much point in parallelizing for small numbers of links - benefits are minimal
-	if len(links) < 10000 {
-		return downsampleComplexSerial(links, outputSize, aggressiveness, debug)
+	if len(links) < ParallelDownsampleThreshold {
+		return downsampleComplexSerial(links, outputSize, aggressiveness, opts, debug)
 	}
+	return downsampleComplexParallel(links, outputSize, aggressiveness, opts, debug)
+}
 
+// downsampleComplexParallel splits links into one chunk per CPU, downsamples
+// the chunks concurrently and stitches them back together, interpolating
+// across chunk boundaries.
+func downsampleComplexParallel[T linkValue](links []T, outputSize int, aggressiveness float64, opts DownsampleOptions, debug bool) []complex128 {
 	if debug {
 		log.Printf("Starting downsampleComplex with %d links and output size %d (aggressiveness: %.2f)",
 			len(links), outputSize, aggressiveness)
 	}
 
 	// Determine view bounds from the links.
-	minX, maxX := real(links[0]), real(links[0])
-	minY, maxY := imag(links[0]), imag(links[0])
-	for _, link := range links {
-		x := real(link)
-		y := imag(link)
-		if x < minX {
-			minX = x
-		}
-		if x > maxX {
-			maxX = x
-		}
-		if y < minY {
-			minY = y
-		}
-		if y > maxY {
-			maxY = y
-		}
-	}
+	view := boundsOf(links)
 	if debug {
-		log.Printf("View bounds: minX=%.6f, maxX=%.6f, minY=%.6f, maxY=%.6f", minX, maxX, minY, maxY)
+		log.Printf("View bounds: minX=%.6f, maxX=%.6f, minY=%.6f, maxY=%.6f", view.MinX, view.MaxX, view.MinY, view.MaxY)
 	}
 
 	// Calculate relative distance between points
-	maxRange := math.Max(maxX-minX, maxY-minY)
+	maxRange := math.Max(view.Width(), view.Height())
 	baseRange := math.Max(0.01, maxRange)
 	relativeSpread := maxRange / baseRange
 	if debug {
@@ -655,9 +1463,9 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 		maxRelativeSpread *= math.Pow(5, aggressiveness)
 	}
 
-	// Add extra smoothing for values between 3.5 and 4.0
-	if aggressiveness > 3.5 {
-		t := (aggressiveness - 3.5) / 0.5
+	// Add extra smoothing for values between the ramp start and the maximum
+	if aggressiveness > aggressivenessRampStart {
+		t := (aggressiveness - aggressivenessRampStart) / (MaxAggressiveness - aggressivenessRampStart)
 		maxRelativeSpread = 0.03 + (0.02 * t)
 	}
 
@@ -678,28 +1486,27 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 		}
 		var sum complex128
 		for _, link := range links {
-			sum += link
+			sum += complex128(link)
 		}
 		avg := sum / complex(float64(len(links)), 0)
 		if debug {
 			log.Printf("Computed average of %d points: %.6f + %.6fi", len(links), real(avg), imag(avg))
 		}
-		return []complex128{avg}
+		return preserveExtremes(links, []complex128{avg}, opts)
 	}
 
 	// Helper to compute pixel coordinate for a link.
 	pixelForLink := func(link complex128) (int, int) {
-		normalizedX := (real(link) - minX) / (maxX - minX)
-		normalizedY := (imag(link) - minY) / (maxY - minY)
-		px := int(math.Round(normalizedX * float64(outputSize)))
-		py := int(math.Round(normalizedY * float64(outputSize)))
+		fx, fy := normalize(link, view, outputSize)
+		px := int(math.Round(fx))
+		py := int(math.Round(fy))
 		return px, py
 	}
 
 	// Calculate interpolation threshold based on aggressiveness
 	interpolationThreshold := 1.1 * math.Pow(2.5, aggressiveness)
-	if aggressiveness > 3.5 {
-		t := (aggressiveness - 3.5) / 0.5
+	if aggressiveness > aggressivenessRampStart {
+		t := (aggressiveness - aggressivenessRampStart) / (MaxAggressiveness - aggressivenessRampStart)
 		interpolationThreshold = 55.0 + (20.0 * t)
 	}
 
@@ -746,13 +1553,13 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 			}
 
 			// Start with the first point in the chunk
-			initPx, initPy := pixelForLink(links[start])
+			initPx, initPy := pixelForLink(complex128(links[start]))
 			currentGroup := groupData{
-				sum:      links[start],
+				sum:      complex128(links[start]),
 				count:    1,
 				pixelX:   initPx,
 				pixelY:   initPy,
-				lastLink: links[start],
+				lastLink: complex128(links[start]),
 			}
 
 			// Helper to flush a group
@@ -762,7 +1569,7 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 
 			// Process points in the chunk
 			for i := start + 1; i < end; i++ {
-				link := links[i]
+				link := complex128(links[i])
 				px, py := pixelForLink(link)
 
 				// Check if this point belongs to current group
@@ -785,16 +1592,15 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 				pixelGap := math.Sqrt(float64(dx*dx + dy*dy))
 
 				if pixelGap > interpolationThreshold {
-					steps := int(pixelGap / math.Pow(2, math.Min(aggressiveness, 3.5)))
-					if aggressiveness > 3.5 {
-						t := (aggressiveness - 3.5) / 0.5
+					steps := int(pixelGap / math.Pow(2, math.Min(aggressiveness, aggressivenessRampStart)))
+					if aggressiveness > aggressivenessRampStart {
+						t := (aggressiveness - aggressivenessRampStart) / (MaxAggressiveness - aggressivenessRampStart)
 						steps = int(float64(steps) * (1.0 - (0.5 * t)))
 					}
 
 					for s := 1; s <= steps; s++ {
 						t := float64(s) / float64(steps+1)
-						interp := currentGroup.lastLink*(1-complex(t, 0)) + link*complex(t, 0)
-						chunkPoints = append(chunkPoints, interp)
+						chunkPoints = append(chunkPoints, gapPoint(links, start, end, i, t, opts.Interpolation))
 					}
 				}
 
@@ -847,7 +1653,8 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 		}
 	}
 
-	// Merge results with interpolation between chunks
+	// Merge results with interpolation between chunks. The seams always
+	// interpolate linearly, since a chunk's neighbors aren't at hand.
 	var finalPoints []complex128
 	for i := 0; i < len(collected); i++ {
 		if len(collected[i].points) == 0 {
@@ -865,9 +1672,9 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 			gap := math.Sqrt(float64(dx*dx + dy*dy))
 
 			if gap > interpolationThreshold {
-				steps := int(gap / math.Pow(2, math.Min(aggressiveness, 3.5)))
-				if aggressiveness > 3.5 {
-					t := (aggressiveness - 3.5) / 0.5
+				steps := int(gap / math.Pow(2, math.Min(aggressiveness, aggressivenessRampStart)))
+				if aggressiveness > aggressivenessRampStart {
+					t := (aggressiveness - aggressivenessRampStart) / (MaxAggressiveness - aggressivenessRampStart)
 					steps = int(float64(steps) * (1.0 - (0.5 * t)))
 				}
 
@@ -884,73 +1691,549 @@ func downsampleComplex(links []complex128, outputSize int, aggressiveness float6
 	if debug {
 		log.Printf("Downsampled %d points to %d points", len(links), len(finalPoints))
 	}
-	return finalPoints
+	return preserveExtremes(links, finalPoints, opts)
+}
+
+// reportRoundTrip logs the reconstruction error of a file just saved, as
+// measured by verify, or why it could not be reloaded.
+func reportRoundTrip(filename string, verify func() (float64, error)) {
+	maxErr, err := verify()
+	if err != nil {
+		log.Printf("Round-trip check of %s failed: %v", filename, err)
+		return
+	}
+	log.Printf("Round-trip check of %s: max reconstruction error %e", filename, maxErr)
+}
+
+// flagSet reports whether the named flag was given on the command line,
+// as opposed to left at its default.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
 
 func main() {
 	// Read command-line flags
 	imagPart := flag.Float64("imag", 6_300_000.0, "Imaginary part of the complex number")
+	minN := flag.Int("minN", 100, "Minimum number of terms")
 	maxN := flag.Int("maxN", 65_000_000_000, "Maximum number of terms")
+	termsFlag := flag.Int("terms", 0, "Sum exactly this many terms, ignoring the |s| heuristic and -minN/-maxN (0 uses the heuristic)")
 	downsampleFlag := flag.Bool("downsample", false, "Enable downsampling of links")
-	aggressiveness := flag.Float64("aggressive", 0.5, "Downsampling aggressiveness (0.0-1.0)")
-	outputFile := flag.String("output", "combined_links.png", "Output filename for the image")
+	aggressiveness := flag.Float64("aggressive", 0.5, "Downsampling aggressiveness (0.0-4.0)")
+	outputFile := flag.String("output", "combined_links.png", "Output filename for the image; {i}, {imag} and {real} are replaced by the run index and the parts of s")
+	outputDirFlag := flag.String("output-dir", "", "Directory to write the image into, created if missing (optional)")
+	batchFlag := flag.String("batch", "", "Comma-separated imaginary parts to render one after another, each to its own -output file (optional)")
 	outputSize := flag.Int("size", 2048, "Output image size in pixels")
 	debugFlag := flag.Bool("debug", false, "Enable debug logging")
 	pointsOnlyFlag := flag.Bool("points", false, "Draw points only, no lines")
+	ticksFlag := flag.Bool("ticks", false, "Draw labelled tick marks at data coordinates")
+	rangeFlag := flag.String("range", "", "Keep only links start:end (half-open, either side optional) for bounds, rendering and saving (optional)")
+	windowFlag := flag.String("window", "", "Render only the data-coordinate rectangle minX,maxX,minY,maxY, clipping segments at its edges (optional)")
+	clipPercentile := flag.Float64("clip-percentile", 0, "Frame the view on this central percentile of links, clamping outliers to the edge (0 disables)")
+	preserveExtremesFlag := flag.Bool("preserve-extremes", false, "Keep the links at the minimum and maximum X and Y when downsampling, so the bounds don't shrink")
+	interpFlag := flag.String("interp", InterpLinear, "How downsampling fills gaps between groups: linear, or cubic to follow a Catmull-Rom spline through the neighboring links")
+	parallelThreshold := flag.Int("parallel-threshold", ParallelDownsampleThreshold, "Link count at which downsampling switches to the parallel path (0 calibrates on this machine)")
+	ditherFlag := flag.Bool("dither", false, "Apply ordered dithering when quantizing the image to 8 bits")
+	statsOnlyFlag := flag.Bool("stats-only", false, "Print the spiral's metrics as JSON on stdout, without rendering or saving anything")
+	noRenderFlag := flag.Bool("no-render", false, "Compute (and save any requested data files) without rendering an image")
+	thumbnailFlag := flag.Int("thumbnail", 0, "Also save a box-filtered thumbnail of this size with a _thumb suffix (0 disables)")
+	ribbonFlag := flag.Bool("ribbon", false, "Draw the chain as a tapering, gradient-shaded filled ribbon")
+	widthBySpeedFlag := flag.Bool("width-by-speed", false, "Scale each segment's line width by its step length")
+	zplotFlag := flag.Bool("zplot", false, "Plot Hardy's Z(t) with its expected amplitude envelope around -imag to -output instead of rendering the spiral")
+	zplotRangeFlag := flag.Float64("zplot-range", 12, "Half-width of the t range -zplot covers around -imag")
+	markerSizeFlag := flag.Float64("marker-size", 0, "Half-width of the -reference marker at the final value, in pixels (0 scales it with -size)")
+	markerStyleFlag := flag.String("marker-style", MarkerX, "Shape of the -reference marker: x, plus or circle")
+	markerColorFlag := flag.String("marker-color", "", "Color of the -reference marker as rrggbb (default orange)")
+	quiverFlag := flag.Int("quiver", 0, "Draw an arrow along the step direction at every Kth link (0 disables)")
+	referenceFlag := flag.Bool("reference", false, "Overlay the unit circle, the labelled real and imaginary axes and a mark at the final value")
+	mirrorFlag := flag.Bool("mirror", false, "Also draw the conjugate spiral, reflected across the real axis, for a symmetric figure")
+	fadeFlag := flag.Float64("fade", 0, "Fade older links out, decaying alpha as exp(-age/tau) with the number of links from the end (0 disables)")
+	colorByResidualFlag := flag.Bool("color-by-residual", false, "Color each segment by its distance from the final value, red far away to white converged")
+	densityAlphaFlag := flag.Bool("density-alpha", false, "Dim segments in dense regions so they don't saturate, keeping sparse tails bright")
+	supersampleFlag := flag.Int("supersample", 1, "Render lines at N times the output size and box-filter down for smoother edges (uses N² more memory)")
+	rendererFlag := flag.String("renderer", RendererDraw2D, "Renderer: draw2d (anti-aliased, with overlay) or simple (plain pixel lines, no draw2d or fonts)")
+	blendFlag := flag.String("blend", BlendAdditive, "How overlapping strokes combine: additive (overlaps brighten, for dense paths) or over (for sparse ones)")
+	paletteFlag := flag.String("palette", PaletteWhite, "Color scheme: white (additive) or heat (density colormap)")
+	windingFlag := flag.Bool("winding", false, "Report the total winding angle of the link chain")
+	noCorrectionFlag := flag.Bool("no-correction", false, "Leave the Euler-Maclaurin correction off the final link (the reported value is still corrected)")
+	diffFlag := flag.String("diff", "", "Compare the render against this reference PNG and save a difference heatmap (optional)")
 	saveDeltaFlag := flag.String("save-delta", "", "Save spiral data using delta compression (optional)")
+	keyframeIntervalFlag := flag.Int("keyframe-interval", 0, "Store every Nth -save-delta point in full so delta rounding error can't accumulate past N points (0 disables)")
+	separateCorrectionFlag := flag.Bool("separate-correction", false, "Store the final link's Euler-Maclaurin correction apart from the -save-delta deltas so its jump doesn't set their scale")
 	saveMsgPackFlag := flag.String("save-msgpack", "", "Save spiral data using MessagePack (optional)")
+	saveVarintFlag := flag.String("save-varint", "", "Save spiral data as zigzag varint grid deltas, smaller than -save-delta for densely sampled chains (optional)")
+	saveHeaderFlag := flag.String("save-header", "", "Save s, N, the point count and bounds as a small MessagePack header, apart from the point data (optional)")
+	parallelGzipFlag := flag.Bool("parallel-gzip", false, "Compress saved spiral data on all cores (output stays gzip-compatible)")
+	saveCSVFlag := flag.String("save-csv", "", "Save links as index,real,imag CSV rows (optional)")
+	saveNDJSONFlag := flag.String("save-ndjson", "", "Save links as newline-delimited JSON, one {\"i\",\"re\",\"im\"} object per line, for streaming consumers (optional)")
+	saveParquetFlag := flag.String("save-parquet", "", "Save links as Parquet with float64 real and imag columns, for pandas or polars (optional)")
+	saveThreeJSFlag := flag.String("save-threejs", "", "Save links as three.js BufferGeometry JSON with x,y,0 positions and bounds (optional)")
+	maxFileMBFlag := flag.Float64("max-file-mb", 0, "Downsample the saved links, raising aggressiveness, until each data file is estimated to fit in this many MB (0 disables)")
+	verifyRoundtripFlag := flag.Bool("verify-roundtrip", false, "Reload each saved data file and log its maximum reconstruction error")
+	inputCSVFlag := flag.String("input-csv", "", "Render links read from an index,real,imag CSV file instead of computing them")
+	methodFlag := flag.String("method", MethodEulerMaclaurin, "How to evaluate ζ(s): euler-maclaurin, auto to use the eta continuation inside the critical strip, or richardson to extrapolate from N and N/2 terms")
+	derivativeFlag := flag.Bool("derivative", false, "Compute and plot the spiral of ζ'(s) = -Σ log(k) k^{-s} instead of ζ(s)")
+	seriesFlag := flag.String("series", SeriesZeta, "Dirichlet series to sum into the spiral: "+strings.Join(seriesNames(), ", "))
+	characterFlag := flag.String("character", "", "Weight term k by this Dirichlet character to plot L(s, χ) instead of ζ(s): "+strings.Join(characterNames(), ", ")+" (optional)")
+	workStealingFlag := flag.Bool("work-stealing", false, "Compute chunks on a fixed worker pool that pulls chunks from a shared counter")
+	sumOrderFlag := flag.String("sum-order", SumAscending, "Order to accumulate terms in: ascending, or descending to add the smallest terms first for a more accurate total")
+	precisionFlag := flag.String("precision", PrecisionFloat64, "Link storage precision: float64, or float32 to halve link memory while downsampling (requires -downsample)")
+	startKFlag := flag.Int("start-k", 1, "First term index to keep as a link; earlier terms only offset the spiral")
+	seedFlag := flag.String("seed", "0,0", "Point the rendered chain starts from, as re,im, or none to start at the first link")
+	dumpChunksFlag := flag.String("dump-chunks", "", "Write each chunk's partial sum and cumulative offset to this CSV file (optional)")
+	targetFPSFlag := flag.Float64("decimate-to-fps", 0, "Cap terms and raise downsampling until compute+render reaches this frame rate (0 disables)")
 	flag.Parse()
 
-	// Set MaxN from the command-line flag
+	// -stats-only keeps stdout for the JSON, so the usual progress output
+	// goes to stderr.
+	var progress io.Writer = os.Stdout
+	if *statsOnlyFlag {
+		progress = os.Stderr
+		for _, name := range []string{"zplot", "dump-chunks", "diff", "thumbnail", "save-delta", "save-msgpack",
+			"save-varint", "save-header", "save-csv", "save-ndjson", "save-parquet", "save-threejs"} {
+			if flagSet(name) {
+				log.Fatalf("-stats-only and -%s cannot be combined: -stats-only writes no files", name)
+			}
+		}
+	}
+
+	// Set MinN and MaxN from the command-line flags
+	MinN = *minN
 	MaxN = *maxN
+	if MinN < 1 || MaxN < MinN {
+		log.Fatalf("need 1 <= -minN <= -maxN, got -minN %d and -maxN %d", MinN, MaxN)
+	}
+	if *termsFlag < 0 {
+		log.Fatalf("-terms must not be negative, got %d", *termsFlag)
+	}
+	CorrectFinalLink = !*noCorrectionFlag
+	seed, prepend, err := parseSeed(*seedFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	compression.ParallelGzip = *parallelGzipFlag
+	if *keyframeIntervalFlag < 0 {
+		log.Fatalf("-keyframe-interval must not be negative, got %d", *keyframeIntervalFlag)
+	}
+	compression.KeyframeInterval = *keyframeIntervalFlag
+	ParallelDownsampleThreshold = *parallelThreshold
+	if ParallelDownsampleThreshold <= 0 {
+		ParallelDownsampleThreshold = calibrateParallelThreshold(*outputSize, *aggressiveness)
+		fmt.Fprintf(progress, "Calibrated parallel downsampling threshold: %d links\n", ParallelDownsampleThreshold)
+	}
+
+	chunks := ChunkConfig{
+		WorkStealing: *workStealingFlag,
+		Order:        *sumOrderFlag,
+		Terms:        *termsFlag,
+		StartK:       *startKFlag,
+		Precision:    *precisionFlag,
+	}
+	if prepend {
+		chunks.Seed = &seed
+	}
+	downsampling := DownsampleOptions{Interpolation: *interpFlag, PreserveExtremes: *preserveExtremesFlag}
+	if *dumpChunksFlag != "" {
+		dumpFile, err := os.Create(*dumpChunksFlag)
+		if err != nil {
+			log.Fatalf("failed to create chunk dump file: %v", err)
+		}
+		defer dumpFile.Close()
+		chunks.Dump = dumpFile
+	}
 
 	start := time.Now()
 
 	// Example complex number with real part 0.5
 	s := complex(0.5, *imagPart)
+	if *imagPart == 0 && *characterFlag == "" && *seriesFlag == SeriesZeta && !*derivativeFlag {
+		log.Println("Note: imag = 0 puts s on the real axis; every term is real, so the spiral degenerates to a line and the total is the analytic continuation ζ(1/2) ≈ -1.4604")
+	}
+
+	renderCfg := RenderConfig{
+		OutputSize:      *outputSize,
+		PointsOnly:      *pointsOnlyFlag,
+		Ticks:           *ticksFlag,
+		ClipPercentile:  *clipPercentile,
+		Dither:          *ditherFlag,
+		Palette:         *paletteFlag,
+		Supersample:     *supersampleFlag,
+		WidthBySpeed:    *widthBySpeedFlag,
+		Ribbon:          *ribbonFlag,
+		Renderer:        *rendererFlag,
+		Blend:           *blendFlag,
+		DensityAlpha:    *densityAlphaFlag,
+		ColorByResidual: *colorByResidualFlag,
+		Fade:            *fadeFlag,
+		Mirror:          *mirrorFlag,
+		Reference:       *referenceFlag,
+		Quiver:          *quiverFlag,
+		MarkerSize:      *markerSizeFlag,
+		MarkerStyle:     *markerStyleFlag,
+	}
+	if *methodFlag != MethodEulerMaclaurin && *methodFlag != MethodAuto && *methodFlag != MethodRichardson {
+		log.Fatalf("unknown method %q (want %q, %q or %q)", *methodFlag, MethodEulerMaclaurin, MethodAuto, MethodRichardson)
+	}
+	chi, useCharacter := Characters[*characterFlag]
+	if *characterFlag != "" && !useCharacter {
+		log.Fatalf("unknown character %q (want one of %s)", *characterFlag, strings.Join(characterNames(), ", "))
+	}
+	if useCharacter && *derivativeFlag {
+		log.Fatal("-character and -derivative cannot be combined")
+	}
+	// -derivative is shorthand for -series derivative.
+	seriesName := *seriesFlag
+	if *derivativeFlag {
+		if seriesName != SeriesZeta && seriesName != SeriesDerivative {
+			log.Fatalf("-derivative cannot be combined with -series %s", seriesName)
+		}
+		seriesName = SeriesDerivative
+	}
+	series, ok := seriesRegistry[seriesName]
+	if !ok {
+		log.Fatalf("unknown series %q (want one of %s)", seriesName, strings.Join(seriesNames(), ", "))
+	}
+	if useCharacter && seriesName != SeriesZeta {
+		log.Fatalf("-character and -series %s cannot be combined", seriesName)
+	}
+	// Only ζ itself has the eta and Richardson alternatives.
+	plainZeta := seriesName == SeriesZeta && !useCharacter
+	if !validSumOrder(*sumOrderFlag) {
+		log.Fatalf("unknown sum order %q (want %q or %q)", *sumOrderFlag, SumAscending, SumDescending)
+	}
+	if !validInterpolation(*interpFlag) {
+		log.Fatalf("unknown interpolation %q (want %q or %q)", *interpFlag, InterpLinear, InterpCubic)
+	}
+	if !validPrecision(*precisionFlag) {
+		log.Fatalf("unknown precision %q (want %q or %q)", *precisionFlag, PrecisionFloat64, PrecisionFloat32)
+	}
+	if *precisionFlag == PrecisionFloat32 && !*downsampleFlag && *inputCSVFlag == "" {
+		// Only downsampling reads complex64 links; everything else would
+		// widen them to complex128, using more memory than float64 alone.
+		log.Fatal("-precision float32 requires -downsample")
+	}
+	if !validRenderer(renderCfg.Renderer) {
+		log.Fatalf("unknown renderer %q (want %q or %q)", renderCfg.Renderer, RendererDraw2D, RendererSimple)
+	}
+	if !validMarkerStyle(renderCfg.MarkerStyle) {
+		log.Fatalf("unknown marker style %q (want %q, %q or %q)", renderCfg.MarkerStyle, MarkerX, MarkerPlus, MarkerCircle)
+	}
+	if *markerColorFlag != "" {
+		c, err := parseHexColor(*markerColorFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		renderCfg.MarkerColor = c
+	}
+	rangeStart, rangeEnd := 0, -1
+	if *rangeFlag != "" {
+		var err error
+		if rangeStart, rangeEnd, err = parseRange(*rangeFlag); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *windowFlag != "" {
+		if renderCfg.ClipPercentile > 0 && renderCfg.ClipPercentile < 100 {
+			log.Fatal("-window and -clip-percentile cannot be combined")
+		}
+		window, err := parseWindow(*windowFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		renderCfg.Bounds = &window
+		renderCfg.ClipSegments = true
+	}
+	if renderCfg.Quiver < 0 {
+		log.Fatalf("-quiver must not be negative, got %d", renderCfg.Quiver)
+	}
+	if renderCfg.Fade < 0 {
+		log.Fatalf("-fade must not be negative, got %v", renderCfg.Fade)
+	}
+	if !validBlend(renderCfg.Blend) {
+		log.Fatalf("unknown blend %q (want %q or %q)", renderCfg.Blend, BlendAdditive, BlendOver)
+	}
+	if !validPalette(renderCfg.Palette) {
+		log.Fatalf("unknown palette %q (want %q or %q)", renderCfg.Palette, PaletteWhite, PaletteHeat)
+	}
+
+	if *batchFlag != "" && *statsOnlyFlag {
+		log.Fatal("-batch and -stats-only cannot be combined")
+	}
+	if *batchFlag != "" && *rangeFlag != "" {
+		log.Fatal("-batch and -range cannot be combined")
+	}
+	if *batchFlag != "" {
+		ts, err := parseBatch(*batchFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		aggr := 0.0
+		if *downsampleFlag {
+			aggr = *aggressiveness
+		}
+		files, err := renderBatch(ts, *outputDirFlag, *outputFile, aggr, downsampling, renderCfg, chunks)
+		if err != nil {
+			log.Fatalf("batch render failed: %v", err)
+		}
+		fmt.Fprintf(progress, "Rendered %d spirals (took %v)\n", len(files), time.Since(start))
+		return
+	}
 
-	// Multi-threaded
-	result, multiThreadedLinks := calculateSpiralPartialSums(s)
+	*outputFile = outputPath(*outputDirFlag, *outputFile, 0, s)
+	if *outputDirFlag != "" && !*statsOnlyFlag {
+		if err := os.MkdirAll(*outputDirFlag, 0o755); err != nil {
+			log.Fatalf("failed to create output directory: %v", err)
+		}
+	}
+
+	if *zplotFlag {
+		// Sample once per pixel column, starting no lower than the zero
+		// scans do.
+		if !(*zplotRangeFlag > 0) {
+			log.Fatalf("-zplot-range must be positive, got %g", *zplotRangeFlag)
+		}
+		tMin := math.Max(*imagPart-*zplotRangeFlag, minZeroT)
+		tMax := *imagPart + *zplotRangeFlag
+		if !(tMax > tMin) {
+			log.Fatalf("-zplot range [%g, %g] is empty: -imag %g plus -zplot-range must exceed %g", *imagPart-*zplotRangeFlag, tMax, *imagPart, minZeroT)
+		}
+		ts, zs := ZCurve(tMin, tMax, (tMax-tMin)/float64(*outputSize))
+		if err := savePNG(renderZCurve(ts, zs, *outputSize), *outputFile); err != nil {
+			log.Fatalf("failed to save Z(t) plot: %v", err)
+		}
+		fmt.Fprintf(progress, "Saved Z(t) plot for t in [%g, %g] to %s (took %v)\n", tMin, tMax, *outputFile, time.Since(start))
+		return
+	}
+
+	termFn, tail := zetaTerm(s), zetaTail(s)
+	switch {
+	case useCharacter:
+		termFn, tail = characterTerm(chi, s), characterTail(chi, s)
+	case !plainZeta:
+		termFn, tail = series.termFunc(s), series.tailFunc(s)
+	}
+
+	if *targetFPSFlag > 0 && *inputCSVFlag == "" {
+		budget := time.Duration(float64(time.Second) / *targetFPSFlag)
+		fullN := termCount(s, chunks.Terms)
+		n, aggr, elapsed := fitFrameBudget(fullN, budget, measureFrame(termFn, tail, downsampling, renderCfg, chunks))
+
+		// Sum exactly the n terms measured, which termCount would otherwise
+		// clamp to [MinN, MaxN].
+		chunks.Terms = n
+		if aggr > 0 {
+			*downsampleFlag = true
+			*aggressiveness = aggr
+		}
+		fmt.Fprintf(progress, "\nFrame budget %v: using N=%d (%.2f%% of %d terms), aggressiveness %.1f, frame time %v\n",
+			budget, n, 100*float64(n)/float64(fullN), fullN, aggr, elapsed)
+		start = time.Now()
+	}
+
+	var result complex128
+	// termsN is the number of terms summed, 0 for a loaded chain.
+	var termsN int
+	var multiThreadedLinks []complex128
+	var links32 []complex64
+	// correction is what was added to the final link, if anything.
+	var correction complex128
+	if *inputCSVFlag != "" {
+		// Externally computed chain: its last link is the final value.
+		links, err := loadLinksCSV(*inputCSVFlag)
+		if err != nil {
+			log.Fatalf("failed to load links: %v", err)
+		}
+		multiThreadedLinks = links
+		result = links[len(links)-1]
+	} else {
+		// Multi-threaded
+		if N := termCount(s, chunks.Terms); chunks.StartK >= N {
+			// Links run from StartK up to N, so there would be none.
+			log.Fatalf("-start-k %d leaves no links: the direct sum stops before N = %d", chunks.StartK, N)
+		}
+		spiral := calculateSeriesPartialSums(s, termFn, tail, chunks)
+		result, multiThreadedLinks, links32 = spiral.Total, spiral.Links, spiral.Links32
+		termsN = spiral.N
+
+		if chunks.Order == SumDescending {
+			// Quantify the accumulation error the descending order avoids.
+			ascendingChunks := chunks
+			ascendingChunks.Order = SumAscending
+			ascending := seriesSum(spiral.N, termFn, tail, ascendingChunks)
+			fmt.Fprintf(progress, "Descending-order total differs from ascending by %e\n", cmplx.Abs(result-ascending))
+		}
+		if CorrectFinalLink {
+			correction = spiral.Correction
+		}
+
+		// The links stay the Euler-Maclaurin spiral; only the reported value changes.
+		if *methodFlag == MethodAuto && plainZeta {
+			if z, ok := ZetaEta(s); ok {
+				log.Printf("Using eta continuation for ζ(s) (Euler-Maclaurin gave %v)", result)
+				result = z
+			}
+		}
+		if *methodFlag == MethodRichardson && plainZeta && spiral.N >= 2 {
+			// The spiral's total is already the sum at N; only N/2 is new.
+			z := richardsonExtrapolate(s, spiral.N, result, seriesSum(spiral.N/2, zetaTerm(s), zetaTail(s), chunks))
+			log.Printf("Using Richardson extrapolation from N=%d and N=%d (Euler-Maclaurin gave %v)", spiral.N, spiral.N/2, result)
+			result = z
+		}
+	}
+
+	if *rangeFlag != "" {
+		total := len(multiThreadedLinks)
+		var err error
+		if links32 != nil {
+			total = len(links32)
+			links32, err = subChain(links32, rangeStart, rangeEnd)
+		} else {
+			multiThreadedLinks, err = subChain(multiThreadedLinks, rangeStart, rangeEnd)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if rangeEnd < 0 {
+			rangeEnd = total
+		}
+		if rangeEnd < total {
+			// The correction was added to the final link, now cut off.
+			correction = 0
+		}
+		if rangeStart > 0 && !flagSet("seed") {
+			// The sub-chain doesn't start from the default seed, so
+			// drawing from it would stretch the view back to the origin.
+			// An explicit -seed is still honoured.
+			chunks.Seed = nil
+		}
+		log.Printf("Kept links %d to %d of %d (-range %s)", rangeStart, rangeEnd, total, *rangeFlag)
+	}
+
+	if *statsOnlyFlag {
+		// Measure the chain as computed, before downsampling thins it.
+		var stats spiralStats
+		if links32 != nil {
+			stats = computeStats(s, result, links32)
+		} else {
+			stats = computeStats(s, result, multiThreadedLinks)
+		}
+		fmt.Fprintf(progress, "\nEuler-Maclaurin result: (%.6f, %.6f)\n", real(result), imag(result))
+		fmt.Fprintf(progress, "Time taken: %v\n", time.Since(start))
+		if err := writeStats(os.Stdout, stats); err != nil {
+			log.Fatalf("failed to write stats: %v", err)
+		}
+		return
+	}
 
 	// Downsample if the flag is set
 	if *downsampleFlag {
 		// Use the same resolution as the final output image.
 		before := len(multiThreadedLinks)
+		bytesPerLink := linkBytes(PrecisionFloat64)
 
 		// Use parallel version by default, but allow fallback to serial for debugging
-		if *debugFlag {
-			multiThreadedLinks = downsampleComplexSerial(multiThreadedLinks, *outputSize, *aggressiveness, *debugFlag)
-		} else {
-			multiThreadedLinks = downsampleComplex(multiThreadedLinks, *outputSize, *aggressiveness, *debugFlag)
+		switch {
+		case links32 != nil:
+			// Downsampling reads the complex64 links directly and returns
+			// the (much smaller) result in complex128.
+			before = len(links32)
+			bytesPerLink = linkBytes(PrecisionFloat32)
+			if *debugFlag {
+				multiThreadedLinks = downsampleComplexSerial(links32, *outputSize, *aggressiveness, downsampling, *debugFlag)
+			} else {
+				multiThreadedLinks = downsampleComplex(links32, *outputSize, *aggressiveness, downsampling, *debugFlag)
+			}
+			links32 = nil
+		case *debugFlag:
+			multiThreadedLinks = downsampleComplexSerial(multiThreadedLinks, *outputSize, *aggressiveness, downsampling, *debugFlag)
+		default:
+			multiThreadedLinks = downsampleComplex(multiThreadedLinks, *outputSize, *aggressiveness, downsampling, *debugFlag)
 		}
 
 		after := len(multiThreadedLinks)
 		// Calculate downsampling statistics
 		reductionRatio := float64(before) / float64(after)
-		memoryBefore := before * 16 // complex128 = 16 bytes
-		memoryAfter := after * 16
+		// The downsampled links are always complex128.
+		memoryBefore := before * bytesPerLink
+		memoryAfter := after * linkBytes(PrecisionFloat64)
 		memorySaved := float64(memoryBefore-memoryAfter) / 1024.0 // Convert to KB
 
-		fmt.Printf("\nDownsampling Statistics (aggressiveness=%.2f):\n", *aggressiveness)
-		fmt.Printf("Points reduced: %d → %d\n", before, after)
-		fmt.Printf("Reduction ratio: %.2fx\n", reductionRatio)
-		fmt.Printf("Memory saved: %.2f KB\n", memorySaved)
-		fmt.Printf("Average distance between points: %.6f\n",
+		fmt.Fprintf(progress, "\nDownsampling Statistics (aggressiveness=%.2f):\n", *aggressiveness)
+		fmt.Fprintf(progress, "Link storage: %d bytes per link before, %d after\n", bytesPerLink, linkBytes(PrecisionFloat64))
+		fmt.Fprintf(progress, "Points reduced: %d → %d\n", before, after)
+		fmt.Fprintf(progress, "Reduction ratio: %.2fx\n", reductionRatio)
+		fmt.Fprintf(progress, "Memory saved: %.2f KB\n", memorySaved)
+		fmt.Fprintf(progress, "Average distance between points: %.6f\n",
 			math.Sqrt(math.Pow(real(multiThreadedLinks[len(multiThreadedLinks)-1]-multiThreadedLinks[0]), 2)+
 				math.Pow(imag(multiThreadedLinks[len(multiThreadedLinks)-1]-multiThreadedLinks[0]), 2))/float64(len(multiThreadedLinks)))
-		fmt.Printf("Maintained visual quality while using %.1f%% fewer points\n",
+		fmt.Fprintf(progress, "Maintained visual quality while using %.1f%% fewer points\n",
 			100.0*(1.0-float64(after)/float64(before)))
 	}
 
 	// Print the final result
-	fmt.Printf("\nEuler-Maclaurin result: (%.6f, %.6f)\n", real(result), imag(result))
+	fmt.Fprintf(progress, "\nEuler-Maclaurin result: (%.6f, %.6f)\n", real(result), imag(result))
+	if *windingFlag {
+		winding := TotalWinding(multiThreadedLinks)
+		fmt.Fprintf(progress, "Total winding: %.6f rad (%.2f turns)\n", winding, winding/(2*math.Pi))
+	}
+	if *debugFlag && len(multiThreadedLinks) > 0 {
+		unique, total, coverage := CoverageStats(multiThreadedLinks, *outputSize)
+		log.Printf("Pixel coverage: %d of %d pixels (%.3f%%)", unique, total, 100*coverage)
+	}
 	elapsed := time.Since(start)
 	fps := 1.0 / elapsed.Seconds()
-	fmt.Printf("Time taken: %v FPS: %.2f\n", elapsed, fps)
+	fmt.Fprintf(progress, "Time taken: %v FPS: %.2f\n", elapsed, fps)
+
+	// The saved links may be thinned to fit -max-file-mb; the render keeps
+	// the full chain.
+	savedLinks := multiThreadedLinks
+	if *maxFileMBFlag > 0 {
+		estimate := func(n int) int64 {
+			var largest int64
+			if *saveDeltaFlag != "" {
+				largest = max(largest, compression.EstimateDeltaSize(n))
+			}
+			if *saveMsgPackFlag != "" {
+				largest = max(largest, compression.EstimateMsgPackSize(n))
+			}
+			if *saveVarintFlag != "" {
+				largest = max(largest, compression.EstimateVarintSize(n))
+			}
+			if *saveCSVFlag != "" {
+				largest = max(largest, estimateCSVSize(n))
+			}
+			if *saveThreeJSFlag != "" {
+				largest = max(largest, estimateThreeJSSize(n))
+			}
+			if *saveNDJSONFlag != "" {
+				largest = max(largest, estimateNDJSONSize(n))
+			}
+			if *saveParquetFlag != "" {
+				largest = max(largest, estimateParquetSize(n))
+			}
+			return largest
+		}
+		maxBytes := int64(*maxFileMBFlag * 1024 * 1024)
+		fitted, aggr, ok := fitToFileSize(savedLinks, maxBytes, estimate, *outputSize, downsampling)
+		if aggr >= 0 {
+			log.Printf("Downsampled saved links %d → %d at aggressiveness %.1f to fit -max-file-mb %.2f (fits: %v)",
+				len(savedLinks), len(fitted), aggr, *maxFileMBFlag, ok)
+		}
+		savedLinks = fitted
+	}
 
 	if *saveDeltaFlag != "" {
 		start := time.Now()
-		compressed, err := compression.CompressWithDelta(multiThreadedLinks)
+		var deltaCorrection complex128
+		if *separateCorrectionFlag {
+			deltaCorrection = correction
+		}
+		compressed, err := compression.CompressWithDeltaCorrection(savedLinks, deltaCorrection)
 		if err != nil {
 			log.Printf("Error compressing with delta encoding: %v", err)
 		} else {
@@ -959,13 +2242,18 @@ func main() {
 			} else {
 				elapsed := time.Since(start)
 				log.Printf("Saved delta compressed data to %s (took %v)", *saveDeltaFlag, elapsed)
+				if *verifyRoundtripFlag {
+					reportRoundTrip(*saveDeltaFlag, func() (float64, error) {
+						return compression.VerifyDeltaFile(*saveDeltaFlag, savedLinks)
+					})
+				}
 			}
 		}
 	}
 
 	if *saveMsgPackFlag != "" {
 		start := time.Now()
-		compressed, err := compression.CompressWithMsgPack(multiThreadedLinks)
+		compressed, err := compression.CompressWithMsgPack(savedLinks)
 		if err != nil {
 			log.Printf("Error compressing with MessagePack: %v", err)
 		} else {
@@ -974,16 +2262,133 @@ func main() {
 			} else {
 				elapsed := time.Since(start)
 				log.Printf("Saved MessagePack data to %s (took %v)", *saveMsgPackFlag, elapsed)
+				if *verifyRoundtripFlag {
+					reportRoundTrip(*saveMsgPackFlag, func() (float64, error) {
+						return compression.VerifyMsgPackFile(*saveMsgPackFlag, savedLinks)
+					})
+				}
+			}
+		}
+	}
+
+	if *saveVarintFlag != "" {
+		start := time.Now()
+		compressed, err := compression.CompressWithVarint(savedLinks)
+		if err != nil {
+			log.Printf("Error compressing with varint encoding: %v", err)
+		} else {
+			if err := compression.SaveVarint(compressed, *saveVarintFlag); err != nil {
+				log.Printf("Error saving varint compressed data: %v", err)
+			} else {
+				elapsed := time.Since(start)
+				log.Printf("Saved varint compressed data to %s (took %v)", *saveVarintFlag, elapsed)
+				if *verifyRoundtripFlag {
+					reportRoundTrip(*saveVarintFlag, func() (float64, error) {
+						return compression.VerifyVarintFile(*saveVarintFlag, savedLinks)
+					})
+				}
 			}
 		}
 	}
 
+	if *saveHeaderFlag != "" {
+		header := compression.NewSpiralHeader(s, termsN, savedLinks)
+		if err := compression.SaveSpiralHeader(header, *saveHeaderFlag); err != nil {
+			log.Printf("Error saving header: %v", err)
+		} else {
+			log.Printf("Saved header to %s", *saveHeaderFlag)
+		}
+	}
+
+	if *saveCSVFlag != "" {
+		start := time.Now()
+		if err := saveLinksCSV(savedLinks, *saveCSVFlag); err != nil {
+			log.Printf("Error saving CSV data: %v", err)
+		} else {
+			elapsed := time.Since(start)
+			log.Printf("Saved CSV data to %s (took %v)", *saveCSVFlag, elapsed)
+			if *verifyRoundtripFlag {
+				reportRoundTrip(*saveCSVFlag, func() (float64, error) {
+					loaded, err := loadLinksCSV(*saveCSVFlag)
+					if err != nil {
+						return 0, err
+					}
+					return compression.ReconstructionError(savedLinks, loaded)
+				})
+			}
+		}
+	}
+
+	if *saveThreeJSFlag != "" {
+		start := time.Now()
+		if err := saveLinksThreeJSON(savedLinks, *saveThreeJSFlag); err != nil {
+			log.Printf("Error saving three.js data: %v", err)
+		} else {
+			elapsed := time.Since(start)
+			log.Printf("Saved three.js data to %s (took %v)", *saveThreeJSFlag, elapsed)
+		}
+	}
+
+	if *saveNDJSONFlag != "" {
+		start := time.Now()
+		if err := saveLinksNDJSON(savedLinks, *saveNDJSONFlag); err != nil {
+			log.Printf("Error saving NDJSON data: %v", err)
+		} else {
+			elapsed := time.Since(start)
+			log.Printf("Saved NDJSON data to %s (took %v)", *saveNDJSONFlag, elapsed)
+		}
+	}
+
+	if *saveParquetFlag != "" {
+		start := time.Now()
+		if err := saveLinksParquet(savedLinks, *saveParquetFlag); err != nil {
+			log.Printf("Error saving Parquet data: %v", err)
+		} else {
+			elapsed := time.Since(start)
+			log.Printf("Saved Parquet data to %s (took %v)", *saveParquetFlag, elapsed)
+		}
+	}
+
+	if *noRenderFlag {
+		log.Println("Skipping render (-no-render)")
+		return
+	}
+
 	// Plot
+	renderCfg.ResidualTarget = result
+	if renderCfg.Reference {
+		renderCfg.ReferencePoint = &result
+	}
 	start = time.Now()
 	println("\nPlotting multi-threaded links")
-	multiThreadedLinks = append([]complex128{complex(0, 0)}, multiThreadedLinks...)
-	plotLinks(multiThreadedLinks, *outputSize, *outputFile, *pointsOnlyFlag) // Pass the points-only flag
+	multiThreadedLinks = seedLinks(multiThreadedLinks, chunks.Seed)
+	finalImage := plotLinks(multiThreadedLinks, renderCfg, *outputFile)
 	elapsed = time.Since(start)
 	fps = 1.0 / elapsed.Seconds()
-	fmt.Printf("Time taken: %v FPS: %.2f\n", elapsed, fps)
+	fmt.Fprintf(progress, "Time taken: %v FPS: %.2f\n", elapsed, fps)
+
+	if *thumbnailFlag > 0 {
+		if *thumbnailFlag >= *outputSize {
+			log.Printf("Skipping thumbnail: size %d is not smaller than the %d pixel render", *thumbnailFlag, *outputSize)
+		} else {
+			thumbFile := strings.TrimSuffix(*outputFile, filepath.Ext(*outputFile)) + "_thumb.png"
+			if err := savePNG(thumbnail(finalImage, *thumbnailFlag), thumbFile); err != nil {
+				log.Fatalf("failed to save thumbnail: %v", err)
+			}
+			log.Println("Thumbnail saved as", thumbFile)
+		}
+	}
+
+	if *diffFlag != "" {
+		reference, err := loadPNG(*diffFlag)
+		if err != nil {
+			log.Fatalf("failed to load reference image: %v", err)
+		}
+		diffImage, score := DiffImages(finalImage, reference)
+		diffFile := strings.TrimSuffix(*outputFile, filepath.Ext(*outputFile)) + "_diff.png"
+		if err := savePNG(diffImage, diffFile); err != nil {
+			log.Fatalf("failed to save diff image: %v", err)
+		}
+		fmt.Fprintf(progress, "Difference score vs %s: %.6f (heatmap saved as %s)\n", *diffFlag, score, diffFile)
+	}
 }
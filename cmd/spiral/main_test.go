@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+
+	"zeta-scale-go/internal/testutil"
+	"zeta-scale-go/pkg/compression"
+)
+
+// runMainEnv, when set, makes the test binary run main with the remaining
+// arguments instead of the tests, so CLI behaviour can be exercised end to
+// end in a subprocess.
+const runMainEnv = "SPIRAL_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) != "" {
+		os.Args = append([]string{os.Args[0]}, os.Args[2:]...)
+		main()
+		os.Exit(0)
+	}
+
+	// Render overlay text with an embedded font so label tests don't
+	// depend on what the host has installed.
+	font, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+	SetFont(font)
+
+	os.Exit(m.Run())
+}
+
+// runSpiral runs main in a subprocess with args and returns its combined
+// output.
+func runSpiral(t *testing.T, args ...string) []byte {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], append([]string{"--"}, args...)...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("spiral %v failed: %v\n%s", args, err, out)
+	}
+	return out
+}
+
+// runSpiralFail is runSpiral for arguments main must reject: it fails t
+// unless the command exits with an error, and returns its output.
+func runSpiralFail(t *testing.T, args ...string) []byte {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], append([]string{"--"}, args...)...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("spiral %v succeeded, want an error\n%s", args, out)
+	}
+	return out
+}
+
+// Test that -no-render still writes requested data files but no image.
+func TestMain_NoRender(t *testing.T) {
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "links.csv")
+	pngFile := filepath.Join(dir, "spiral.png")
+
+	runSpiral(t, "-imag", "1000", "-no-render", "-save-csv", csvFile, "-output", pngFile)
+
+	if info, err := os.Stat(csvFile); err != nil || info.Size() == 0 {
+		t.Errorf("expected CSV output at %s: %v", csvFile, err)
+	}
+	if _, err := os.Stat(pngFile); !os.IsNotExist(err) {
+		t.Errorf("expected no PNG at %s, got err %v", pngFile, err)
+	}
+}
+
+// Test that -verify-roundtrip reloads the saved file and logs a finite error.
+func TestMain_VerifyRoundtrip(t *testing.T) {
+	deltaFile := filepath.Join(t.TempDir(), "links.delta")
+
+	out := runSpiral(t, "-imag", "1000", "-no-render", "-save-delta", deltaFile, "-verify-roundtrip")
+
+	match := regexp.MustCompile(`Round-trip check of .*: max reconstruction error (\S+)`).FindSubmatch(out)
+	if match == nil {
+		t.Fatalf("no round-trip report in output:\n%s", out)
+	}
+	maxErr, err := strconv.ParseFloat(string(match[1]), 64)
+	if err != nil || math.IsInf(maxErr, 0) || math.IsNaN(maxErr) {
+		t.Errorf("got reconstruction error %q, want a finite number", match[1])
+	}
+}
+
+// Test that with -precision float32 the downsampling stats count 8 bytes per
+// computed link.
+func TestMain_Float32MemoryStats(t *testing.T) {
+	out := runSpiral(t, "-imag", "1000", "-no-render", "-downsample", "-precision", "float32")
+
+	if !regexp.MustCompile(`Link storage: 8 bytes per link before, 16 after`).Match(out) {
+		t.Errorf("no float32 storage line in output:\n%s", out)
+	}
+	points := regexp.MustCompile(`Points reduced: (\d+) → (\d+)`).FindSubmatch(out)
+	saved := regexp.MustCompile(`Memory saved: (\S+) KB`).FindSubmatch(out)
+	if points == nil || saved == nil {
+		t.Fatalf("no downsampling stats in output:\n%s", out)
+	}
+	before, _ := strconv.Atoi(string(points[1]))
+	after, _ := strconv.Atoi(string(points[2]))
+	want := fmt.Sprintf("%.2f", float64(before*8-after*16)/1024)
+	if got := string(saved[1]); got != want {
+		t.Errorf("got %s KB saved for %d → %d links, want %s", got, before, after, want)
+	}
+}
+
+// Test that -precision float32 is rejected without -downsample, which would
+// otherwise widen the whole chain back to complex128.
+func TestMain_Float32RequiresDownsample(t *testing.T) {
+	out := runSpiralFail(t, "-imag", "1000", "-no-render", "-precision", "float32")
+	if !bytes.Contains(out, []byte("requires -downsample")) {
+		t.Errorf("unexpected error output:\n%s", out)
+	}
+}
+
+// Test that a tight -max-file-mb downsamples the saved links until the file
+// fits.
+func TestMain_MaxFileMB(t *testing.T) {
+	deltaFile := filepath.Join(t.TempDir(), "links.delta")
+	limitMB := 0.05
+
+	out := runSpiral(t, "-imag", "200000", "-size", "512", "-no-render",
+		"-max-file-mb", strconv.FormatFloat(limitMB, 'f', -1, 64), "-save-delta", deltaFile)
+
+	if !regexp.MustCompile(`Downsampled saved links \d+ → \d+ at aggressiveness \S+ to fit`).Match(out) {
+		t.Errorf("no downsampling report in output:\n%s", out)
+	}
+	info, err := os.Stat(deltaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit := int64(limitMB * 1024 * 1024); info.Size() > limit {
+		t.Errorf("got %d byte file, want at most %d", info.Size(), limit)
+	}
+}
+
+// Test that -stats-only prints only JSON on stdout, with every metric
+// present and finite, and writes no image.
+func TestMain_StatsOnly(t *testing.T) {
+	pngFile := filepath.Join(t.TempDir(), "spiral.png")
+	cmd := exec.Command(os.Args[0], "--", "-imag", "1000", "-stats-only", "-output", pngFile)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("spiral -stats-only failed: %v", err)
+	}
+
+	var stats map[string]any
+	if err := json.Unmarshal(out, &stats); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, out)
+	}
+	for _, field := range []string{"s", "zeta", "links", "windingRad", "windingTurns", "signedArea", "bounds", "coverage", "steps"} {
+		if _, ok := stats[field]; !ok {
+			t.Errorf("missing field %q", field)
+		}
+	}
+	// Every number, however deeply nested, must be finite, and the step
+	// histogram must have at least one decade.
+	var check func(path string, v any)
+	check = func(path string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, e := range v {
+				check(path+"."+k, e)
+			}
+		case []any:
+			if len(v) == 0 {
+				t.Errorf("%s is empty", path)
+			}
+			for i, e := range v {
+				check(fmt.Sprintf("%s[%d]", path, i), e)
+			}
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				t.Errorf("%s = %v, want finite", path, v)
+			}
+		default:
+			t.Errorf("%s has unexpected value %v", path, v)
+		}
+	}
+	check("stats", stats)
+	if steps := stats["steps"].(map[string]any); steps["count"].(float64) < 1 {
+		t.Errorf("got %v steps, want some", steps["count"])
+	}
+
+	if _, err := os.Stat(pngFile); !os.IsNotExist(err) {
+		t.Errorf("-stats-only wrote %s", pngFile)
+	}
+}
+
+// Test that -stats-only measures the chain before -downsample thins it, and
+// rejects flags that would write files.
+func TestMain_StatsOnlyFullChain(t *testing.T) {
+	links := func(args ...string) float64 {
+		t.Helper()
+		cmd := exec.Command(os.Args[0], append([]string{"--", "-imag", "1000", "-stats-only"}, args...)...)
+		cmd.Env = append(os.Environ(), runMainEnv+"=1")
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("spiral -stats-only %v failed: %v", args, err)
+		}
+		var stats struct{ Links float64 }
+		if err := json.Unmarshal(out, &stats); err != nil {
+			t.Fatalf("stdout is not JSON: %v\n%s", err, out)
+		}
+		return stats.Links
+	}
+	full := links()
+	if got := links("-downsample", "-aggressive", "4"); got != full {
+		t.Errorf("got %v links with -downsample, want the full chain's %v", got, full)
+	}
+	if got := links("-downsample", "-precision", "float32"); got != full {
+		t.Errorf("got %v links with float32 storage, want the full chain's %v", got, full)
+	}
+
+	csvFile := filepath.Join(t.TempDir(), "links.csv")
+	out := runSpiralFail(t, "-imag", "1000", "-stats-only", "-save-csv", csvFile)
+	if !bytes.Contains(out, []byte("-stats-only and -save-csv cannot be combined")) {
+		t.Errorf("want a -save-csv error, got:\n%s", out)
+	}
+	if _, err := os.Stat(csvFile); !os.IsNotExist(err) {
+		t.Errorf("-stats-only wrote %s", csvFile)
+	}
+}
+
+// Test that -range exports exactly the requested links of the full chain.
+func TestMain_Range(t *testing.T) {
+	dir := t.TempDir()
+	fullFile := filepath.Join(dir, "full.csv")
+	rangeFile := filepath.Join(dir, "range.csv")
+
+	runSpiral(t, "-imag", "1000", "-no-render", "-save-csv", fullFile)
+	runSpiral(t, "-imag", "1000", "-no-render", "-range", "100:250", "-save-csv", rangeFile)
+
+	full, err := loadLinksCSV(fullFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := loadLinksCSV(rangeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sub) != 150 {
+		t.Fatalf("got %d links, want 150", len(sub))
+	}
+	for i, link := range sub {
+		if link != full[100+i] {
+			t.Fatalf("link %d of the range is %v, want link %d of the chain, %v", i, link, 100+i, full[100+i])
+		}
+	}
+}
+
+// Test that a -start-k at or past the term count N is rejected rather than
+// leaving downsampling and the savers an empty chain.
+func TestMain_StartKPastN(t *testing.T) {
+	out := runSpiralFail(t, "-imag", "1000", "-no-render", "-start-k", "100000000", "-downsample")
+	if bytes.Contains(out, []byte("panic")) || !bytes.Contains(out, []byte("-start-k 100000000 leaves no links")) {
+		t.Errorf("want a -start-k error, got:\n%s", out)
+	}
+}
+
+// Test that -range rejects ranges selecting no links, whether written empty
+// or running past the chain, before anything downstream dereferences them.
+func TestMain_RangeEmpty(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"-range", "5:5", "-save-delta", filepath.Join(dir, "links.delta")},
+		{"-range", "5:5", "-save-varint", filepath.Join(dir, "links.varint")},
+		{"-range", "5:5", "-downsample"},
+		{"-range", "100000000:", "-downsample"},
+	} {
+		out := runSpiralFail(t, append([]string{"-imag", "1000", "-no-render"}, args...)...)
+		if bytes.Contains(out, []byte("panic")) || !bytes.Contains(out, []byte("-range")) {
+			t.Errorf("spiral %v: want a -range error, got:\n%s", args, out)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("empty ranges wrote %d files", len(entries))
+	}
+}
+
+// Test that -zplot rejects a zero -zplot-range and a range lying wholly
+// below the lowest plotted height.
+func TestMain_ZPlotEmptyRange(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "z.png")
+	for _, args := range [][]string{
+		{"-imag", "20", "-zplot-range", "0"},
+		{"-imag", "-50", "-zplot-range", "12"},
+	} {
+		out := runSpiralFail(t, append(args, "-zplot", "-output", output)...)
+		if bytes.Contains(out, []byte("panic")) {
+			t.Errorf("spiral %v panicked:\n%s", args, out)
+		}
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Errorf("spiral %v wrote %s", args, output)
+		}
+	}
+}
+
+// Test that -save-header records N = 0 for a chain loaded with -input-csv,
+// whose term count is unknown, rather than its link count.
+func TestMain_SaveHeaderInputCSV(t *testing.T) {
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "links.csv")
+	headerFile := filepath.Join(dir, "links.header")
+	if err := saveLinksCSV(testutil.SpiralLinks(500), csvFile); err != nil {
+		t.Fatal(err)
+	}
+
+	runSpiral(t, "-input-csv", csvFile, "-no-render", "-save-header", headerFile)
+
+	header, err := compression.LoadSpiralHeader(headerFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.N != 0 || header.NumPoints != 500 {
+		t.Errorf("got N %d and %d points, want N 0 and 500 points", header.N, header.NumPoints)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+)
+
+// Marker styles accepted by RenderConfig.MarkerStyle.
+const (
+	MarkerX      = "x"
+	MarkerPlus   = "plus"
+	MarkerCircle = "circle"
+)
+
+// validMarkerStyle reports whether name is a known marker style. The empty
+// string selects the default x.
+func validMarkerStyle(name string) bool {
+	return name == "" || name == MarkerX || name == MarkerPlus || name == MarkerCircle
+}
+
+// minMarkerSize is the smallest default marker half-width, in output
+// pixels; above it the default grows with the image so the marker stays
+// visible on large renders.
+const minMarkerSize = 6.0
+
+// markerSize returns the marker's half-width in pixels for an image of
+// outputSize: size if positive, otherwise proportional to outputSize.
+func markerSize(size float64, outputSize int) float64 {
+	if size > 0 {
+		return size
+	}
+	return math.Max(minMarkerSize, float64(outputSize)/128)
+}
+
+// parseHexColor parses an opaque color written as rrggbb, with or without a
+// leading #.
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: want rrggbb", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: %v", s, err)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
+
+// drawMarker draws a marker of the given style centred on (x, y), reaching
+// arm pixels out from the centre, with a stroke that thickens with it.
+func drawMarker(gc *draw2dimg.GraphicContext, x, y, arm float64, style string, c color.RGBA) {
+	gc.SetLineWidth(math.Max(2, arm/6))
+	gc.SetStrokeColor(c)
+	gc.BeginPath()
+	switch style {
+	case MarkerPlus:
+		gc.MoveTo(x-arm, y)
+		gc.LineTo(x+arm, y)
+		gc.MoveTo(x, y-arm)
+		gc.LineTo(x, y+arm)
+	case MarkerCircle:
+		gc.ArcTo(x, y, arm, arm, 0, 2*math.Pi)
+		gc.Close()
+	default:
+		gc.MoveTo(x-arm, y-arm)
+		gc.LineTo(x+arm, y+arm)
+		gc.MoveTo(x-arm, y+arm)
+		gc.LineTo(x+arm, y-arm)
+	}
+	gc.Stroke()
+}
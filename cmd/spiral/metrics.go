@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// TotalWinding sums the signed turning angle between consecutive link steps.
+// Each angle is taken from the ratio of successive steps, so it always lies in
+// (-π, π] and the ±π branch cut never introduces a spurious full turn.
+// Zero-length steps carry no direction and are skipped.
+func TotalWinding[T linkValue](links []T) float64 {
+	var total float64
+	var prevStep complex128
+
+	for i := 1; i < len(links); i++ {
+		step := complex128(links[i]) - complex128(links[i-1])
+		if step == 0 {
+			continue
+		}
+		if prevStep != 0 {
+			total += cmplx.Phase(step / prevStep)
+		}
+		prevStep = step
+	}
+	return total
+}
+
+// SignedArea returns the area enclosed by the chain closed back to its first
+// link, by the shoelace formula: positive when it runs counter-clockwise,
+// negative when clockwise. Regions wound around more than once count once
+// per turn, so for a spiral it grows with both size and winding. Steps are
+// taken relative to the first link to avoid cancellation far from the
+// origin.
+func SignedArea[T linkValue](links []T) float64 {
+	if len(links) < 3 {
+		return 0
+	}
+	origin := complex128(links[0])
+	var twice float64
+	for i := 2; i < len(links); i++ {
+		a, b := complex128(links[i-1])-origin, complex128(links[i])-origin
+		twice += real(a)*imag(b) - imag(a)*real(b)
+	}
+	return twice / 2
+}
+
+// CoverageStats reports how many distinct pixels of an outputSize x
+// outputSize render the links fall on, out of the total pixel count. Near
+// full coverage suggests a larger output or less downsampling; a tiny
+// fraction suggests a smaller output would lose nothing.
+func CoverageStats[T linkValue](links []T, outputSize int) (uniquePixels, totalPixels int, coverage float64) {
+	totalPixels = outputSize * outputSize
+	if len(links) == 0 || totalPixels == 0 {
+		return 0, totalPixels, 0
+	}
+
+	view := boundsOf(links)
+	hit := make([]bool, totalPixels)
+	pixel := func(v float64) int {
+		return min(max(int(v), 0), outputSize-1)
+	}
+	for _, link := range links {
+		fx, fy := normalize(complex128(link), view, outputSize)
+		idx := pixel(fy)*outputSize + pixel(fx)
+		if !hit[idx] {
+			hit[idx] = true
+			uniquePixels++
+		}
+	}
+	return uniquePixels, totalPixels, float64(uniquePixels) / float64(totalPixels)
+}
+
+// suggestAggressivenessSteps is how many bisection steps
+// SuggestAggressiveness takes, each one full downsampling pass.
+const suggestAggressivenessSteps = 10
+
+// SuggestAggressiveness recommends a downsampling aggressiveness whose output
+// covers roughly targetCoverage of an outputSize x outputSize render, as
+// measured by CoverageStats. Coverage falls as aggressiveness rises, so it
+// bisects the supported range and returns the tried value whose coverage
+// came closest. A target above the coverage at MinAggressiveness returns
+// MinAggressiveness, and one below the coverage at MaxAggressiveness
+// returns MaxAggressiveness.
+func SuggestAggressiveness(links []complex128, outputSize int, targetCoverage float64) float64 {
+	coverageAt := func(aggressiveness float64) float64 {
+		_, _, coverage := CoverageStats(downsampleComplex(links, outputSize, aggressiveness, DownsampleOptions{}, false), outputSize)
+		return coverage
+	}
+
+	lo, hi := MinAggressiveness, MaxAggressiveness
+	if coverageAt(lo) <= targetCoverage {
+		return lo
+	}
+	if coverageAt(hi) >= targetCoverage {
+		return hi
+	}
+
+	best, bestErr := lo, math.Inf(1)
+	for i := 0; i < suggestAggressivenessSteps; i++ {
+		mid := (lo + hi) / 2
+		coverage := coverageAt(mid)
+		if err := math.Abs(coverage - targetCoverage); err < bestErr {
+			best, bestErr = mid, err
+		}
+		if coverage > targetCoverage {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return best
+}
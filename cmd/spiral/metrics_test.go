@@ -0,0 +1,120 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Test that a spiral making three counter-clockwise turns winds by about 6π.
+func TestTotalWinding(t *testing.T) {
+	n := 3000
+	links := make([]complex128, n)
+	for i := 0; i < n; i++ {
+		u := float64(i) / float64(n-1)
+		links[i] = cmplx.Rect(1+u, u*3*2*math.Pi)
+	}
+
+	got := TotalWinding(links)
+	if !floatEquals(got, 6*math.Pi, 0.2) {
+		t.Errorf("got winding %f, want approximately %f", got, 6*math.Pi)
+	}
+
+	// Reversing the chain turns the other way.
+	reversed := make([]complex128, n)
+	for i := range links {
+		reversed[n-1-i] = links[i]
+	}
+	if rev := TotalWinding(reversed); !floatEquals(rev, -got, 1e-9) {
+		t.Errorf("got reversed winding %f, want %f", rev, -got)
+	}
+}
+
+// Test that a counter-clockwise unit square has area 1, and reversing it
+// flips the sign.
+func TestSignedArea(t *testing.T) {
+	square := []complex128{complex(2, 3), complex(3, 3), complex(3, 4), complex(2, 4)}
+	if got := SignedArea(square); !floatEquals(got, 1, 1e-12) {
+		t.Errorf("got area %f, want 1", got)
+	}
+
+	reversed := make([]complex128, len(square))
+	for i := range square {
+		reversed[len(square)-1-i] = square[i]
+	}
+	if got := SignedArea(reversed); !floatEquals(got, -1, 1e-12) {
+		t.Errorf("got reversed area %f, want -1", got)
+	}
+
+	if got := SignedArea(square[:2]); got != 0 {
+		t.Errorf("got area %f for a single segment, want 0", got)
+	}
+}
+
+// Test the bounding-box aspect ratio and area on a known chain.
+func TestChain_AspectRatioAndArea(t *testing.T) {
+	c := Chain{complex(-1, 2), complex(3, 0.5), complex(0, -1), complex(1, 1)}
+	minX, maxX, minY, maxY := -1.0, 3.0, -1.0, 2.0
+
+	if got, want := c.AspectRatio(), (maxX-minX)/(maxY-minY); !floatEquals(got, want, 1e-12) {
+		t.Errorf("got aspect ratio %f, want %f", got, want)
+	}
+	if got, want := c.BoundingArea(), (maxX-minX)*(maxY-minY); !floatEquals(got, want, 1e-12) {
+		t.Errorf("got bounding area %f, want %f", got, want)
+	}
+
+	if got := (Chain{complex(0, 1), complex(2, 1)}).AspectRatio(); !math.IsInf(got, 1) {
+		t.Errorf("got aspect ratio %f for a horizontal chain, want +Inf", got)
+	}
+	if got := (Chain{complex(1, 1)}).AspectRatio(); got != 1 {
+		t.Errorf("got aspect ratio %f for a single point, want 1", got)
+	}
+}
+
+// Test that a dense diagonal line covers about one pixel per column.
+func TestCoverageStats(t *testing.T) {
+	outputSize := 200
+	n := 10000
+	links := make([]complex128, n)
+	for i := range links {
+		u := float64(i) / float64(n-1)
+		links[i] = complex(u, u)
+	}
+
+	unique, total, coverage := CoverageStats(links, outputSize)
+	if total != outputSize*outputSize {
+		t.Errorf("got %d total pixels, want %d", total, outputSize*outputSize)
+	}
+	if unique < outputSize-1 || unique > outputSize+1 {
+		t.Errorf("got %d unique pixels, want about %d", unique, outputSize)
+	}
+	if !floatEquals(coverage, float64(unique)/float64(total), 1e-12) {
+		t.Errorf("got coverage %f, want %f", coverage, float64(unique)/float64(total))
+	}
+}
+
+// Test that downsampling at the suggested aggressiveness covers close to
+// the target fraction of pixels, and that out-of-reach targets clamp to the
+// ends of the range.
+func TestSuggestAggressiveness(t *testing.T) {
+	links := calculateSpiralPartialSums(complex(0.5, 20000), ChunkConfig{}).Links
+	const outputSize = 256
+	_, _, full := CoverageStats(downsampleComplex(links, outputSize, MinAggressiveness, DownsampleOptions{}, false), outputSize)
+	_, _, sparse := CoverageStats(downsampleComplex(links, outputSize, MaxAggressiveness, DownsampleOptions{}, false), outputSize)
+
+	target := (full + sparse) / 2
+	a := SuggestAggressiveness(links, outputSize, target)
+	_, _, got := CoverageStats(downsampleComplex(links, outputSize, a, DownsampleOptions{}, false), outputSize)
+	t.Logf("coverage %.4f at aggressiveness 0, %.4f at max; suggested %.3f gives %.4f for target %.4f",
+		full, sparse, a, got, target)
+	if math.Abs(got-target) > 0.1*target {
+		t.Errorf("got coverage %.4f at suggested aggressiveness %.3f, want within 10%% of %.4f", got, a, target)
+	}
+
+	if a := SuggestAggressiveness(links, outputSize, 1); a != MinAggressiveness {
+		t.Errorf("got %v for full coverage, want %v", a, MinAggressiveness)
+	}
+	if a := SuggestAggressiveness(links, outputSize, 0); a != MaxAggressiveness {
+		t.Errorf("got %v for zero coverage, want %v", a, MaxAggressiveness)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"zeta-scale-go/pkg/compression"
+)
+
+// ndjsonFlushInterval is how many lines ExportNDJSON writes between flushes
+// of a buffered writer, so a consumer reading the output as it grows sees
+// it arrive in steady batches.
+const ndjsonFlushInterval = 4096
+
+// ndjsonLink is one line of ExportNDJSON's output.
+type ndjsonLink struct {
+	Index int     `json:"i"`
+	Real  float64 `json:"re"`
+	Imag  float64 `json:"im"`
+}
+
+// ExportNDJSON writes the links as newline-delimited JSON, one
+// {"i":..,"re":..,"im":..} object per line, so a consumer can process them
+// as they are written instead of loading a whole array. Every line stands on
+// its own and carries its index, so output cut short can be resumed from
+// the last complete line. If w has a Flush method, such as a bufio.Writer,
+// it is flushed every ndjsonFlushInterval lines.
+func ExportNDJSON(links []complex128, w io.Writer) error {
+	flusher, _ := w.(interface{ Flush() error })
+	enc := json.NewEncoder(w)
+	for i, link := range links {
+		if err := enc.Encode(ndjsonLink{Index: i, Real: real(link), Imag: imag(link)}); err != nil {
+			return err
+		}
+		if flusher != nil && (i+1)%ndjsonFlushInterval == 0 {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// saveLinksNDJSON exports links to an NDJSON file. The file is written
+// atomically, so it only appears once complete; streaming consumers read
+// ExportNDJSON's output from a pipe or socket instead.
+func saveLinksNDJSON(links []complex128, filename string) error {
+	return compression.WriteFileAtomic(filename, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		if err := ExportNDJSON(links, bw); err != nil {
+			return err
+		}
+		return bw.Flush()
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// countingFlusher is a buffer that counts Flush calls.
+type countingFlusher struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *countingFlusher) Flush() error {
+	f.flushes++
+	return nil
+}
+
+// Test that ExportNDJSON writes one parseable line per link, in order and
+// exactly, flushing as it goes.
+func TestExportNDJSON(t *testing.T) {
+	links := testutil.SpiralLinks(2*ndjsonFlushInterval + 7)
+	var out countingFlusher
+	if err := ExportNDJSON(links, &out); err != nil {
+		t.Fatalf("ExportNDJSON: %v", err)
+	}
+	if out.flushes != 2 {
+		t.Errorf("got %d flushes, want 2", out.flushes)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(&out.Buffer)
+	for scanner.Scan() {
+		var got ndjsonLink
+		if err := json.Unmarshal(scanner.Bytes(), &got); err != nil {
+			t.Fatalf("line %d doesn't parse: %v: %q", lines, err, scanner.Text())
+		}
+		if got.Index != lines || complex(got.Real, got.Imag) != links[lines] {
+			t.Fatalf("line %d: got %+v, want index %d and %v", lines, got, lines, links[lines])
+		}
+		lines++
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if lines != len(links) {
+		t.Errorf("got %d lines, want one per link (%d)", lines, len(links))
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+// Palettes accepted by RenderConfig.Palette.
+const (
+	PaletteWhite = "white"
+	PaletteHeat  = "heat"
+)
+
+// heatLUT is the heat colormap sampled at 256 levels.
+var heatLUT = func() [256]color.RGBA {
+	var lut [256]color.RGBA
+	for i := range lut {
+		lut[i] = heatColor(float64(i) / 255)
+	}
+	return lut
+}()
+
+// validPalette reports whether name is a known palette. The empty string
+// selects the default white palette.
+func validPalette(name string) bool {
+	return name == "" || name == PaletteWhite || name == PaletteHeat
+}
+
+// densityColor maps a pixel's accumulated hit density (in full-opacity hits)
+// through the heat colormap. The exponential saturates gradually, so a
+// single faint pass stays dark red while heavily overlapped pixels reach white.
+func densityColor(density float64) color.RGBA {
+	level := 1 - math.Exp(-density)
+	return heatLUT[int(math.Round(level*255))]
+}
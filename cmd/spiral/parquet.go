@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+
+	"zeta-scale-go/pkg/compression"
+)
+
+// parquetBatchSize is how many rows ExportParquet converts and writes at a
+// time, so a long chain is never copied into rows all at once.
+const parquetBatchSize = 4096
+
+// parquetLink is one row of ExportParquet's output.
+type parquetLink struct {
+	Real float64 `parquet:"real"`
+	Imag float64 `parquet:"imag"`
+}
+
+// ExportParquet writes the links as a Parquet file with float64 columns
+// real and imag, one row per link in order, for loading into columnar
+// tools such as pandas or polars.
+func ExportParquet(links []complex128, w io.Writer) error {
+	pw := parquet.NewGenericWriter[parquetLink](w)
+	rows := make([]parquetLink, 0, min(len(links), parquetBatchSize))
+	for start := 0; start < len(links); start += parquetBatchSize {
+		rows = rows[:0]
+		for _, link := range links[start:min(start+parquetBatchSize, len(links))] {
+			rows = append(rows, parquetLink{Real: real(link), Imag: imag(link)})
+		}
+		if _, err := pw.Write(rows); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
+
+// saveLinksParquet exports links to a Parquet file, atomically.
+func saveLinksParquet(links []complex128, filename string) error {
+	return compression.WriteFileAtomic(filename, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		if err := ExportParquet(links, bw); err != nil {
+			return err
+		}
+		return bw.Flush()
+	})
+}
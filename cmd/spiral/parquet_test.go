@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that ExportParquet's real and imag columns read back exactly, in
+// order, across several write batches.
+func TestExportParquet(t *testing.T) {
+	links := testutil.SpiralLinks(2*parquetBatchSize + 7)
+	var buf bytes.Buffer
+	if err := ExportParquet(links, &buf); err != nil {
+		t.Fatalf("ExportParquet: %v", err)
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	for _, name := range []string{"real", "imag"} {
+		col, ok := file.Schema().Lookup(name)
+		if !ok {
+			t.Fatalf("no %q column", name)
+		}
+		if kind := col.Node.Type().Kind(); kind != parquet.Double {
+			t.Errorf("column %q has type %v, want DOUBLE", name, kind)
+		}
+	}
+
+	rows, err := parquet.Read[parquetLink](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(rows) != len(links) {
+		t.Fatalf("got %d rows, want one per link (%d)", len(rows), len(links))
+	}
+	for i, row := range rows {
+		if got := complex(row.Real, row.Imag); got != links[i] {
+			t.Fatalf("row %d: got %v, want %v", i, got, links[i])
+		}
+	}
+}
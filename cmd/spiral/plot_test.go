@@ -0,0 +1,779 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/cmplx"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that a chain with no extent along X is centered rather than dropped.
+func TestRenderLinks_DegenerateRange(t *testing.T) {
+	links := []complex128{
+		complex(1, 1),
+		complex(1, 2),
+	}
+	outputSize := 64
+
+	img := renderLinks(links, RenderConfig{OutputSize: outputSize})
+	if img.Bounds().Dx() != outputSize || img.Bounds().Dy() != outputSize {
+		t.Fatalf("got image %v, want %dx%d", img.Bounds(), outputSize, outputSize)
+	}
+
+	// The vertical line should land on the center column.
+	center := outputSize / 2
+	lit := 0
+	for y := 0; y < outputSize; y++ {
+		for x := center - 1; x <= center; x++ {
+			if img.RGBAAt(x, y).R > 30 {
+				lit++
+				break
+			}
+		}
+	}
+	if lit < outputSize/2 {
+		t.Errorf("got %d lit rows near the center column, want at least %d", lit, outputSize/2)
+	}
+
+	// Nothing should be drawn away from the center.
+	for y := 0; y < outputSize; y++ {
+		if c := img.RGBAAt(2, y); c.R > 30 {
+			t.Fatalf("unexpected mark at (2, %d): %v", y, c)
+		}
+	}
+}
+
+// Test that tick labels are drawn at the normalized position of their value.
+func TestRenderLinks_TickLabels(t *testing.T) {
+	// Keep the path along the top and right edges so the bottom is free for labels.
+	links := []complex128{
+		complex(0, 2),
+		complex(2, 2),
+		complex(2, 0),
+	}
+	outputSize := 256
+
+	// Bounds span [0, 2], so ticks fall every 0.5 and the "1.0" label is centered.
+	if step := niceTickStep(2, 5); step != 0.5 {
+		t.Fatalf("got tick step %v, want 0.5", step)
+	}
+
+	img := renderLinks(links, RenderConfig{OutputSize: outputSize, Ticks: true})
+
+	brightNear := func(cx, cy, radius int) bool {
+		for y := cy - radius; y <= cy+radius; y++ {
+			for x := cx - radius; x <= cx+radius; x++ {
+				if img.RGBAAt(x, y).R > 200 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// Label for 1.0 sits just above the bottom edge at the center.
+	if !brightNear(outputSize/2, outputSize-16, 6) {
+		t.Errorf("expected tick label pixels near (%d, %d)", outputSize/2, outputSize-16)
+	}
+	// Halfway between the 0.5 and 1.0 ticks there should be no label.
+	if brightNear(3*outputSize/8, outputSize-16, 2) {
+		t.Errorf("unexpected label pixels between ticks near (%d, %d)", 3*outputSize/8, outputSize-16)
+	}
+
+	// Without ticks the same region stays dark.
+	plain := renderLinks(links, RenderConfig{OutputSize: outputSize})
+	if c := plain.RGBAAt(outputSize/2, outputSize-16); c.R > 200 {
+		t.Errorf("unexpected bright pixel without ticks: %v", c)
+	}
+}
+
+// Test that a single far outlier no longer squeezes the spiral into a corner
+// when the view is clipped to a central percentile.
+func TestRenderLinks_ClipPercentile(t *testing.T) {
+	n := 2000
+	links := make([]complex128, 0, n+1)
+	for i := 0; i < n; i++ {
+		u := float64(i) / float64(n)
+		links = append(links, cmplx.Rect(0.1+0.9*u, u*5*2*math.Pi))
+	}
+	links = append(links, complex(1000, 1000))
+	outputSize := 128
+
+	// Count lit pixels in the central quarter of the frame.
+	centerLit := func(cfg RenderConfig) int {
+		img := renderLinks(links, cfg)
+		lit := 0
+		for y := outputSize / 4; y < 3*outputSize/4; y++ {
+			for x := outputSize / 4; x < 3*outputSize/4; x++ {
+				if img.RGBAAt(x, y).R > 60 {
+					lit++
+				}
+			}
+		}
+		return lit
+	}
+
+	unclipped := centerLit(RenderConfig{OutputSize: outputSize, PointsOnly: true})
+	clipped := centerLit(RenderConfig{OutputSize: outputSize, PointsOnly: true, ClipPercentile: 99})
+
+	if unclipped != 0 {
+		t.Errorf("expected the outlier to push the cluster out of the center, got %d lit pixels", unclipped)
+	}
+	if clipped < outputSize {
+		t.Errorf("expected the clipped cluster to fill the center, got only %d lit pixels", clipped)
+	}
+
+	b := percentileBounds(links, 99)
+	if b.MaxX > 1 || b.MaxY > 1 || b.MinX < -1 || b.MinY < -1 {
+		t.Errorf("percentile bounds include the outlier: X [%f, %f], Y [%f, %f]", b.MinX, b.MaxX, b.MinY, b.MaxY)
+	}
+}
+
+// Test that dithering a shallow brightness ramp spreads it over more 8-bit
+// levels than plain rounding, and does so deterministically.
+func TestQuantize_DitherRamp(t *testing.T) {
+	width, height := 64, 8
+	distinct := func(dither bool) map[uint8]bool {
+		levels := make(map[uint8]bool)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				// Ramp from 30.0 to 30.45: plain rounding flattens it to 30.
+				v := 30 + 0.45*float64(x)/float64(width-1)
+				levels[quantize(v, x, y, dither)] = true
+			}
+		}
+		return levels
+	}
+
+	plain := distinct(false)
+	dithered := distinct(true)
+	if len(dithered) <= len(plain) {
+		t.Errorf("got %d distinct dithered levels, want more than %d without dithering", len(dithered), len(plain))
+	}
+
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if quantize(30.3, x, y, true) != quantize(30.3, x+4, y+8, true) {
+				t.Fatalf("dither pattern is not deterministic at (%d, %d)", x, y)
+			}
+		}
+	}
+
+	// Whole levels are unaffected by the dither threshold.
+	if got := quantize(200, 1, 2, true); got != 200 {
+		t.Errorf("got %d for an exact level, want 200", got)
+	}
+}
+
+// Test that denser pixels map to hotter heat-palette colors.
+func TestDensityColor_Ordering(t *testing.T) {
+	brightness := func(density float64) int {
+		c := densityColor(density)
+		return int(c.R) + int(c.G) + int(c.B)
+	}
+
+	low, high := brightness(0.25), brightness(4)
+	if high <= low {
+		t.Errorf("high density brightness %d not hotter than low density %d", high, low)
+	}
+	if c := densityColor(0.25); c.R == 0 || c.B != 0 {
+		t.Errorf("low density should be a dark red, got %v", c)
+	}
+	if c := densityColor(100); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("saturated density should be white, got %v", c)
+	}
+
+}
+
+// Test that each further pass over a pixel renders it hotter under the heat
+// palette, by the same amount however many workers split the passes.
+func TestRenderLinks_HeatRisesWithHits(t *testing.T) {
+	defer func(n int) { renderWorkers = n }(renderWorkers)
+	cfg := RenderConfig{OutputSize: 64, Palette: PaletteHeat}
+	center := func(passes int) int {
+		// A path back and forth along the same diagonal.
+		links := []complex128{0}
+		for i := 1; i <= passes; i++ {
+			links = append(links, complex(float64(i%2), float64(i%2)))
+		}
+		c := renderLinks(links, cfg).RGBAAt(32, 32)
+		return int(c.R) + int(c.G) + int(c.B)
+	}
+
+	var want []int
+	for _, workers := range []int{1, 2, 5} {
+		renderWorkers = workers
+		var got []int
+		for passes := 1; passes <= 6; passes++ {
+			got = append(got, center(passes))
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i] <= got[i-1] {
+				t.Errorf("%d workers: %d passes brightness %d not hotter than %d passes' %d", workers, i+1, got[i], i, got[i-1])
+			}
+		}
+		if want == nil {
+			want = got
+		} else if !slices.Equal(got, want) {
+			t.Errorf("%d workers: brightness by passes %v, want %v as with one worker", workers, got, want)
+		}
+	}
+}
+
+// Test that supersampling a diagonal line spreads its edge over more
+// intermediate brightness levels than a single-pass render.
+func TestRenderLinks_SupersampleGradation(t *testing.T) {
+	// The closing vertical leg widens the Y range so the diagonal is not 45°.
+	links := []complex128{0, complex(1, 0.37), complex(1, 1)}
+	outputSize := 64
+
+	edgeLevels := func(supersample int) int {
+		img := renderLinks(links, RenderConfig{OutputSize: outputSize, Supersample: supersample})
+		levels := make(map[uint8]bool)
+		for y := 0; y < outputSize; y++ {
+			for x := 0; x < outputSize; x++ {
+				// Partially covered pixels sit between the background and full stroke.
+				if r := img.RGBAAt(x, y).R; r > 30 && r < 255 {
+					levels[r] = true
+				}
+			}
+		}
+		return len(levels)
+	}
+
+	single := edgeLevels(1)
+	super := edgeLevels(4)
+	t.Logf("edge levels: single %d, supersampled %d", single, super)
+	if super <= single {
+		t.Errorf("got %d distinct edge levels with supersampling, want more than %d", super, single)
+	}
+}
+
+// Test that with width-by-speed a long step is stroked thicker across its
+// direction than a short one.
+func TestRenderLinks_WidthBySpeed(t *testing.T) {
+	// A small-step diagonal lead-in, one long horizontal step, then small
+	// vertical steps. The offset keeps both axes out of the frame.
+	offset := complex(10, 10)
+	var links []complex128
+	for i := 0; i <= 10; i++ {
+		u := float64(i) / 10
+		links = append(links, offset+complex(-1+u, -1+u))
+	}
+	links = append(links, offset+complex(4, 0))
+	for i := 1; i <= 10; i++ {
+		links = append(links, offset+complex(4, float64(i)/10))
+	}
+	for i := 1; i <= 10; i++ {
+		u := float64(i) / 10
+		links = append(links, offset+complex(4+u, 1+u))
+	}
+
+	// Bounds are X [9, 15] and Y [9, 12] on a 120px frame.
+	outputSize := 120
+	img := renderLinks(links, RenderConfig{OutputSize: outputSize, WidthBySpeed: true})
+	lit := func(x, y int) bool { return img.RGBAAt(x, y).R > 40 }
+
+	// Across the long horizontal step at x = 12 (column 60, row 80).
+	long := 0
+	for y := 60; y < 100; y++ {
+		if lit(60, y) {
+			long++
+		}
+	}
+	// Across the short vertical steps at y = 10.5 (row 60, column 100).
+	short := 0
+	for x := 80; x < outputSize; x++ {
+		if lit(x, 60) {
+			short++
+		}
+	}
+
+	if long <= short {
+		t.Errorf("long step lit %d pixels across, want more than the %d of a short step", long, short)
+	}
+	if long < 4 {
+		t.Errorf("long step lit only %d pixels across, want a thick stroke", long)
+	}
+}
+
+// Test that a thumbnail has the requested size and matches a box downscale
+// of the full render.
+func TestThumbnail(t *testing.T) {
+	full := renderLinks(testutil.SpiralLinks(2000), RenderConfig{OutputSize: 256})
+
+	thumb := thumbnail(full, 64)
+	if thumb.Bounds().Dx() != 64 || thumb.Bounds().Dy() != 64 {
+		t.Fatalf("got thumbnail %v, want 64x64", thumb.Bounds())
+	}
+
+	// For an integer factor the thumbnail is exactly the box downscale.
+	box := boxDownsample(full, 4)
+	for i := range thumb.Pix {
+		if thumb.Pix[i] != box.Pix[i] {
+			t.Fatalf("thumbnail differs from box downscale at byte %d: %d vs %d", i, thumb.Pix[i], box.Pix[i])
+		}
+	}
+
+	// A non-integer factor keeps the same overall brightness.
+	odd := thumbnail(full, 100)
+	if odd.Bounds().Dx() != 100 || odd.Bounds().Dy() != 100 {
+		t.Fatalf("got thumbnail %v, want 100x100", odd.Bounds())
+	}
+	mean := func(img *image.RGBA) float64 {
+		var sum float64
+		for i := 0; i < len(img.Pix); i += 4 {
+			sum += float64(img.Pix[i])
+		}
+		return sum / float64(len(img.Pix)/4)
+	}
+	if got, want := mean(odd), mean(full); math.Abs(got-want) > 1 {
+		t.Errorf("got mean brightness %.2f, want about %.2f", got, want)
+	}
+}
+
+// Test that the ribbon render fills more pixels than the thin line.
+func TestRenderLinks_Ribbon(t *testing.T) {
+	links := testutil.SpiralLinks(500)
+	outputSize := 128
+
+	litPixels := func(cfg RenderConfig) int {
+		img := renderLinks(links, cfg)
+		lit := 0
+		for y := 0; y < outputSize; y++ {
+			for x := 0; x < outputSize; x++ {
+				if c := img.RGBAAt(x, y); c.R > 40 {
+					lit++
+				}
+			}
+		}
+		return lit
+	}
+
+	line := litPixels(RenderConfig{OutputSize: outputSize})
+	ribbon := litPixels(RenderConfig{OutputSize: outputSize, Ribbon: true})
+	if ribbon <= line {
+		t.Errorf("ribbon lit %d pixels, want more than the %d of the thin line", ribbon, line)
+	}
+}
+
+// Test that text drawn with the font set by SetFont lands on the image.
+func TestSetFont_RendersLabel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+	gc := draw2dimg.NewGraphicContext(img)
+	gc.SetFillColor(color.White)
+	gc.SetFontData(overlayFont)
+	gc.SetFontSize(14)
+	gc.FillStringAt("1.0", 4, 24)
+
+	lit := 0
+	for i := 0; i < len(img.Pix); i += 4 {
+		if img.Pix[i] > 128 {
+			lit++
+		}
+	}
+	if lit < 10 {
+		t.Errorf("got %d text pixels, want the label to be drawn", lit)
+	}
+}
+
+// Test that density-aware brightness keeps a heavily overlapped cluster
+// below white while a lone segment stays visible.
+func TestRenderLinks_DensityAlpha(t *testing.T) {
+	// A small circle traced many times, then one long sparse leg.
+	var links []complex128
+	for i := 0; i < 40000; i++ {
+		links = append(links, cmplx.Rect(0.05, float64(i)*2*math.Pi/200))
+	}
+	for i := 1; i <= 10; i++ {
+		links = append(links, complex(0.05+0.095*float64(i), 0))
+	}
+	outputSize := 128
+
+	peak := func(img *image.RGBA) uint8 {
+		var p uint8
+		for y := 0; y < outputSize; y++ {
+			for x := 0; x < outputSize/4; x++ {
+				p = max(p, img.RGBAAt(x, y).R)
+			}
+		}
+		return p
+	}
+
+	plain := renderLinks(links, RenderConfig{OutputSize: outputSize})
+	dimmed := renderLinks(links, RenderConfig{OutputSize: outputSize, DensityAlpha: true})
+	if got := peak(plain); got != 255 {
+		t.Fatalf("expected the plain cluster to saturate, got peak %d", got)
+	}
+	t.Logf("cluster peak: plain %d, density alpha %d", peak(plain), peak(dimmed))
+	if got := peak(dimmed); got >= 255 {
+		t.Errorf("got cluster peak %d with density alpha, want below 255", got)
+	}
+
+	// The sparse leg runs along y = 0 near the vertical middle.
+	visible := false
+	for y := outputSize/2 - 2; y <= outputSize/2+2; y++ {
+		if dimmed.RGBAAt(3*outputSize/4, y).R > 60 {
+			visible = true
+		}
+	}
+	if !visible {
+		t.Error("sparse segment is not visible with density alpha")
+	}
+}
+
+// Test that normalize puts the view's corners on the image corners, with
+// Y flipped, and centers a degenerate axis.
+func TestNormalize_Corners(t *testing.T) {
+	b := Bounds{MinX: -2, MaxX: 6, MinY: 1, MaxY: 5}
+	size := 100
+
+	testCases := []struct {
+		link   complex128
+		fx, fy float64
+	}{
+		{complex(-2, 1), 0, 100},  // min corner → bottom left
+		{complex(6, 5), 100, 0},   // max corner → top right
+		{complex(-2, 5), 0, 0},    // top left
+		{complex(6, 1), 100, 100}, // bottom right
+		{complex(2, 3), 50, 50},   // center
+	}
+	for _, tc := range testCases {
+		fx, fy := normalize(tc.link, b, size)
+		if fx != tc.fx || fy != tc.fy {
+			t.Errorf("normalize(%v): got (%v, %v), want (%v, %v)", tc.link, fx, fy, tc.fx, tc.fy)
+		}
+	}
+
+	flat := Bounds{MinX: 1, MaxX: 1, MinY: 0, MaxY: 2}
+	if fx, fy := normalize(complex(1, 2), flat, size); fx != 50 || fy != 0 {
+		t.Errorf("degenerate X: got (%v, %v), want (50, 0)", fx, fy)
+	}
+}
+
+// Test that with Fade the last link is drawn at full alpha and an early link
+// at reduced alpha.
+func TestRenderLinks_Fade(t *testing.T) {
+	// A diagonal line, clear of the axes, with well-separated segments.
+	n := 64
+	links := make([]complex128, n)
+	for i := range links {
+		v := 1 + float64(i)/float64(n-1)
+		links[i] = complex(v, v)
+	}
+	outputSize := 128
+
+	// peak returns the brightest red around the midpoint of segment i.
+	peak := func(img *image.RGBA, i int) uint8 {
+		mid := (links[i-1] + links[i]) / 2
+		view, _ := viewBounds(links, RenderConfig{OutputSize: outputSize})
+		cx, cy := normalize(mid, view, outputSize)
+		var p uint8
+		for y := int(cy) - 1; y <= int(cy)+1; y++ {
+			for x := int(cx) - 1; x <= int(cx)+1; x++ {
+				p = max(p, img.RGBAAt(x, y).R)
+			}
+		}
+		return p
+	}
+
+	// A huge tau fades nothing, giving the full-alpha reference.
+	full := renderLinks(links, RenderConfig{OutputSize: outputSize, Fade: 1e12})
+	faded := renderLinks(links, RenderConfig{OutputSize: outputSize, Fade: 8})
+
+	if got, want := peak(faded, n-1), peak(full, n-1); got != want {
+		t.Errorf("last segment: got peak %d, want the full-alpha %d", got, want)
+	}
+	if got, want := peak(faded, 2), peak(full, 2); got >= want {
+		t.Errorf("early segment: got peak %d, want below the full-alpha %d", got, want)
+	}
+	if f := fadeLevel(n-1, n, 8); f != 1 {
+		t.Errorf("fadeLevel of the last link: got %v, want 1", f)
+	}
+}
+
+// Test that the mirror chain is the conjugate of the links and that Mirror
+// draws both it and the links.
+func TestRenderLinks_Mirror(t *testing.T) {
+	// An arc well above the real axis.
+	var links []complex128
+	for i := 0; i <= 100; i++ {
+		links = append(links, complex(1, 0.5)+cmplx.Rect(0.25, math.Pi*float64(i)/100))
+	}
+	for i, m := range mirrorLinks(links) {
+		if m != cmplx.Conj(links[i]) {
+			t.Fatalf("mirrored link %d: got %v, want %v", i, m, cmplx.Conj(links[i]))
+		}
+	}
+
+	// Fix the view so the links fall in the top half and their mirror in
+	// the bottom half, clear of the real axis across the middle.
+	outputSize := 128
+	view := &Bounds{MinX: 0.5, MaxX: 1.5, MinY: -1, MaxY: 1}
+	lit := func(img *image.RGBA, y0, y1 int) bool {
+		for y := y0; y < y1; y++ {
+			for x := 0; x < outputSize; x++ {
+				if img.RGBAAt(x, y).R > 100 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, renderer := range []string{RendererDraw2D, RendererSimple} {
+		for _, mirror := range []bool{false, true} {
+			img := renderLinks(links, RenderConfig{OutputSize: outputSize, Bounds: view, Mirror: mirror, Renderer: renderer})
+			if !lit(img, 0, outputSize/2-4) {
+				t.Errorf("%s, mirror %v: the links are not drawn in the top half", renderer, mirror)
+			}
+			if got := lit(img, outputSize/2+4, outputSize); got != mirror {
+				t.Errorf("%s, mirror %v: got bottom half lit %v, want %v", renderer, mirror, got, mirror)
+			}
+		}
+	}
+
+	// Without fixed bounds the view widens to frame both halves.
+	if got, want := mirrorView(Bounds{MinX: 0, MaxX: 1, MinY: 0.25, MaxY: 0.75}), (Bounds{MinX: 0, MaxX: 1, MinY: -0.75, MaxY: 0.75}); got != want {
+		t.Errorf("mirrorView: got %+v, want %+v", got, want)
+	}
+}
+
+// Test that the Reference overlay draws the unit circle at the radius the
+// view maps it to.
+func TestRenderLinks_ReferenceCircle(t *testing.T) {
+	// A short segment in a corner, clear of the circle and the axes.
+	links := []complex128{complex(1.8, 1.8), complex(1.9, 1.9)}
+	outputSize := 200
+	img := renderLinks(links, RenderConfig{
+		OutputSize: outputSize,
+		Bounds:     &Bounds{MinX: -2, MaxX: 2, MinY: -2, MaxY: 2},
+		Reference:  true,
+	})
+
+	// The view is 4 wide, so the unit circle has a radius of a quarter of
+	// the image around its center. Skip the axes through the center.
+	center := float64(outputSize) / 2
+	wantRadius := float64(outputSize) / 4
+	var count int
+	var sum, worst float64
+	for y := 0; y < outputSize; y++ {
+		for x := 0; x < outputSize; x++ {
+			c := img.RGBAAt(x, y)
+			dx, dy := float64(x)+0.5-center, float64(y)+0.5-center
+			if int(c.B) < int(c.R)+40 || math.Abs(dx) < 3 || math.Abs(dy) < 3 {
+				continue
+			}
+			r := math.Hypot(dx, dy)
+			if r > 3*wantRadius/2 {
+				continue // axis labels
+			}
+			count++
+			sum += r
+			worst = math.Max(worst, math.Abs(r-wantRadius))
+		}
+	}
+	if count < 100 {
+		t.Fatalf("found only %d circle pixels", count)
+	}
+	if mean := sum / float64(count); math.Abs(mean-wantRadius) > 1 {
+		t.Errorf("got mean radius %.2f, want %.2f", mean, wantRadius)
+	}
+	if worst > 2 {
+		t.Errorf("a circle pixel lies %.2f px off the expected radius", worst)
+	}
+}
+
+// Test that quiver arrows start at the sampled links and point along their
+// steps: right for a step in +Re, up for a step in +Im.
+func TestRenderLinks_Quiver(t *testing.T) {
+	// Two sampled links, far apart and away from the axes.
+	links := []complex128{complex(-1.5, -1.5), complex(-1.4, -1.5), complex(1.2, 1), complex(1.2, 1.1)}
+	outputSize := 200
+	img := renderLinks(links, RenderConfig{
+		OutputSize: outputSize,
+		Bounds:     &Bounds{MinX: -2, MaxX: 2, MinY: -2, MaxY: 2},
+		Quiver:     2,
+	})
+
+	// Arrow pixels are green, unlike the white links and grey background.
+	arrowNear := func(x0, y0, dx, dy int) (ahead, behind int) {
+		for step := 3; step < int(quiverLength); step++ {
+			for _, dir := range []int{1, -1} {
+				c := img.RGBAAt(x0+dir*step*dx, y0+dir*step*dy)
+				if int(c.G) > int(c.R)+60 {
+					if dir == 1 {
+						ahead++
+					} else {
+						behind++
+					}
+				}
+			}
+		}
+		return ahead, behind
+	}
+
+	// links[0] is at pixel (25, 175); its arrow should run right.
+	if ahead, behind := arrowNear(25, 175, 1, 0); ahead < 5 || behind > 0 {
+		t.Errorf("first arrow: got %d pixels to the right and %d to the left, want it pointing right", ahead, behind)
+	}
+	// links[2] is at pixel (160, 50); +Im is up the image.
+	if ahead, behind := arrowNear(160, 50, 0, -1); ahead < 5 || behind > 0 {
+		t.Errorf("second arrow: got %d pixels above and %d below, want it pointing up", ahead, behind)
+	}
+}
+
+// Test that a large plus marker spans its configured size around the final
+// value, and that the default size grows with the output.
+func TestRenderLinks_MarkerSize(t *testing.T) {
+	links := []complex128{complex(-1.9, -1.9), complex(-1.8, -1.9)}
+	point := complex(1, 1)
+	outputSize := 200
+	const arm = 40
+	img := renderLinks(links, RenderConfig{
+		OutputSize:     outputSize,
+		Bounds:         &Bounds{MinX: -2, MaxX: 2, MinY: -2, MaxY: 2},
+		Reference:      true,
+		ReferencePoint: &point,
+		MarkerSize:     arm,
+		MarkerStyle:    MarkerPlus,
+		MarkerColor:    color.RGBA{255, 0, 0, 255},
+	})
+
+	// 1+1i is at pixel (150, 50). Find the red extent along each arm.
+	isMarker := func(x, y int) bool {
+		c := img.RGBAAt(x, y)
+		return c.R > 200 && c.G < 80 && c.B < 80
+	}
+	cx, cy := 150, 50
+	minX, maxX, minY, maxY := cx, cx, cy, cy
+	for x := 0; x < outputSize; x++ {
+		if isMarker(x, cy) {
+			minX, maxX = min(minX, x), max(maxX, x)
+		}
+	}
+	for y := 0; y < outputSize; y++ {
+		if isMarker(cx, y) {
+			minY, maxY = min(minY, y), max(maxY, y)
+		}
+	}
+	for name, extent := range map[string]int{"left": cx - minX, "right": maxX - cx, "up": cy - minY, "down": maxY - cy} {
+		if extent < arm-2 || extent > arm+2 {
+			t.Errorf("marker reaches %d px %s, want about %d", extent, name, arm)
+		}
+	}
+	// A plus leaves the diagonals clear.
+	if isMarker(cx+arm/2, cy+arm/2) {
+		t.Errorf("found marker pixels on the diagonal of a plus")
+	}
+
+	if small, large := markerSize(0, 256), markerSize(0, 4096); small != minMarkerSize || large <= small {
+		t.Errorf("got default sizes %v at 256 px and %v at 4096 px, want %v and larger", small, large, minMarkerSize)
+	}
+}
+
+// Test that ClipSegments cuts a segment crossing into the window at the
+// edge, where clamping would have bent it into the corner, and that links
+// outside the window draw nothing.
+func TestRenderLinks_ClipSegments(t *testing.T) {
+	// The first segment enters through the left edge halfway up, the second
+	// leaves through the top, and the rest run around outside the window,
+	// which is kept clear of the origin's axes.
+	links := []complex128{
+		complex(0, 1), complex(1.5, 1.75), complex(1.5, 3),
+		complex(4, 3), complex(4, -1), complex(-2, -1),
+	}
+	outputSize := 100
+	lit := func(img *image.RGBA, x, y int) bool {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if img.RGBAAt(x+dx, y+dy).R > 40 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, renderer := range []string{RendererDraw2D, RendererSimple} {
+		img := renderLinks(links, RenderConfig{
+			OutputSize:   outputSize,
+			Bounds:       &Bounds{MinX: 1, MaxX: 2, MinY: 1, MaxY: 2},
+			ClipSegments: true,
+			Renderer:     renderer,
+		})
+		// (0, 1)-(1.5, 1.75) crosses x = 1 at y = 1.5.
+		if !lit(img, 1, 50) {
+			t.Errorf("%s: the crossing segment is not drawn where it meets the left edge", renderer)
+		}
+		if lit(img, 1, 97) {
+			t.Errorf("%s: the crossing segment is drawn to the corner as if clamped", renderer)
+		}
+		if !lit(img, 25, 38) {
+			t.Errorf("%s: the crossing segment is not drawn inside the window", renderer)
+		}
+		// Clamped, the outside links would run along the right and bottom
+		// edges and the top edge away from where the chain leaves.
+		for i := 1; i < outputSize-1; i++ {
+			if lit(img, outputSize-2, i) || lit(img, i, outputSize-2) {
+				t.Fatalf("%s: links outside the window drew pixels along the right or bottom edge", renderer)
+			}
+			if (i < 45 || i > 55) && lit(img, i, 1) {
+				t.Fatalf("%s: links outside the window drew pixels along the top edge at x=%d", renderer, i)
+			}
+		}
+	}
+
+	if _, err := parseWindow("0,1,0"); err == nil {
+		t.Error("parseWindow: expected an error for three values")
+	}
+	if _, err := parseWindow("1,0,0,1"); err == nil {
+		t.Error("parseWindow: expected an error for minX above maxX")
+	}
+	if got, err := parseWindow("-1, 1, -2, 2"); err != nil || got != (Bounds{MinX: -1, MaxX: 1, MinY: -2, MaxY: 2}) {
+		t.Errorf("parseWindow: got %+v, %v", got, err)
+	}
+}
+
+// Test that with more workers than links, the workers left without links
+// allocate no layer and the render still draws the links.
+func TestRenderLinks_MoreWorkersThanLinks(t *testing.T) {
+	const workers = 64
+	defer func(n int) { renderWorkers = n }(renderWorkers)
+	renderWorkers = workers
+
+	// Points, since each worker gets a single link and so no segment.
+	links := []complex128{0, complex(1, 1), complex(2, 0)}
+	outputSize := 256
+	cfg := RenderConfig{OutputSize: outputSize, PointsOnly: true}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	img := renderLinks(links, cfg)
+	runtime.ReadMemStats(&after)
+
+	lit := 0
+	for y := 0; y < outputSize; y++ {
+		for x := 0; x < outputSize; x++ {
+			if img.RGBAAt(x, y).R > 100 {
+				lit++
+			}
+		}
+	}
+	if lit < len(links) {
+		t.Errorf("got %d lit pixels, want at least one per link", lit)
+	}
+
+	// One layer per worker would allocate 64 of them; the three links need
+	// at most three, plus the final image and overlay.
+	layer := uint64(4 * outputSize * outputSize)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16*layer {
+		t.Errorf("allocated %d bytes, want under %d with most workers idle", allocated, 16*layer)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+// Storage precisions for the links, selected with -precision. Terms are
+// always summed in float64; float32 only narrows the stored links.
+const (
+	PrecisionFloat64 = "float64"
+	PrecisionFloat32 = "float32"
+)
+
+// linkValue is the element type of a stored link chain.
+type linkValue interface {
+	~complex64 | ~complex128
+}
+
+// validPrecision reports whether p names a known storage precision.
+func validPrecision(p string) bool {
+	return p == PrecisionFloat64 || p == PrecisionFloat32
+}
+
+// linkBytes returns the memory one stored link takes at precision p.
+func linkBytes(p string) int {
+	if p == PrecisionFloat32 {
+		return 8 // complex64
+	}
+	return 16 // complex128
+}
+
+// narrowLinks converts links to complex64 storage.
+func narrowLinks(links []complex128) []complex64 {
+	narrowed := make([]complex64, len(links))
+	for i, link := range links {
+		narrowed[i] = complex64(link)
+	}
+	return narrowed
+}
+
+// widenLinks converts complex64 links back to complex128.
+func widenLinks(links []complex64) []complex128 {
+	widened := make([]complex128, len(links))
+	for i, link := range links {
+		widened[i] = complex128(link)
+	}
+	return widened
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+)
+
+// Arrow geometry for RenderConfig.Quiver, in output pixels. Every arrow has
+// the same length so slow and fast steps are equally visible; only the
+// direction carries information.
+const (
+	quiverLength = 14.0
+	quiverHead   = 5.0
+)
+
+// quiverColor keeps the arrows apart from the white spiral and the blue
+// reference overlay.
+var quiverColor = color.RGBA{90, 230, 120, 255}
+
+// drawQuiver draws an arrow at every every-th link of links pointing along
+// its step links[i+1]-links[i], framed in view like the links themselves.
+// Zero-length steps have no direction and get no arrow, and unless clip
+// pins links to the frame, neither do links outside it.
+func drawQuiver(img *image.RGBA, links []complex128, view Bounds, clip bool, every int) {
+	size := img.Bounds().Dx()
+	gc := draw2dimg.NewGraphicContext(img)
+	gc.SetStrokeColor(quiverColor)
+	gc.SetLineWidth(1.5)
+
+	for i := 0; i+1 < len(links); i += every {
+		x0, y0 := framePoint(links[i], view, size, clip)
+		x1, y1 := framePoint(links[i+1], view, size, clip)
+		if x0 == x1 && y0 == y1 || !inFrame(x0, y0, float64(size)) {
+			continue
+		}
+		// The direction is taken on screen, so it matches the drawn step
+		// under any aspect ratio of the view.
+		angle := math.Atan2(y1-y0, x1-x0)
+		tipX, tipY := x0+quiverLength*math.Cos(angle), y0+quiverLength*math.Sin(angle)
+
+		gc.BeginPath()
+		gc.MoveTo(x0, y0)
+		gc.LineTo(tipX, tipY)
+		for _, side := range []float64{-1, 1} {
+			back := angle + math.Pi + side*math.Pi/6
+			gc.MoveTo(tipX, tipY)
+			gc.LineTo(tipX+quiverHead*math.Cos(back), tipY+quiverHead*math.Sin(back))
+		}
+		gc.Stroke()
+	}
+}
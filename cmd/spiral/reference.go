@@ -0,0 +1,74 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/cmplx"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+)
+
+// referenceCircleSegments is how many chords approximate the unit circle.
+const referenceCircleSegments = 256
+
+// Colors of the RenderConfig.Reference overlay: blue geometry and labels
+// so it stands apart from the white spiral, and an orange convergence mark
+// unless MarkerColor overrides it.
+var (
+	referenceColor = color.RGBA{70, 130, 220, 220}
+	referenceMark  = color.RGBA{255, 150, 40, 255}
+)
+
+// drawReference draws the Reference overlay onto gc: the unit circle, the
+// real and imaginary axes with labels, and cfg's marker at ReferencePoint
+// when it is set. All of it is placed in data coordinates through
+// normalize, like the links, so it lines up with the spiral under any view.
+func drawReference(gc *draw2dimg.GraphicContext, view Bounds, size int, cfg RenderConfig) {
+	full := float64(size)
+	gc.SetLineWidth(1)
+	gc.SetStrokeColor(referenceColor)
+	gc.SetFillColor(referenceColor)
+
+	gc.BeginPath()
+	for i := 0; i <= referenceCircleSegments; i++ {
+		x, y := normalize(cmplx.Rect(1, 2*math.Pi*float64(i)/referenceCircleSegments), view, size)
+		if i == 0 {
+			gc.MoveTo(x, y)
+		} else {
+			gc.LineTo(x, y)
+		}
+	}
+	gc.Stroke()
+
+	x0, y0 := normalize(0, view, size)
+	if view.MinY <= 0 && view.MaxY >= 0 {
+		gc.BeginPath()
+		gc.MoveTo(0, y0)
+		gc.LineTo(full, y0)
+		gc.Stroke()
+		// Label above the axis, or below it when it runs along the top.
+		left, top, right, bottom := gc.GetStringBounds("Re")
+		ly := y0 - 4
+		if ly < bottom-top {
+			ly = y0 + bottom - top + 4
+		}
+		gc.FillStringAt("Re", full-(right-left)-4, ly)
+	}
+	if view.MinX <= 0 && view.MaxX >= 0 {
+		gc.BeginPath()
+		gc.MoveTo(x0, 0)
+		gc.LineTo(x0, full)
+		gc.Stroke()
+		_, top, _, bottom := gc.GetStringBounds("Im")
+		gc.FillStringAt("Im", x0+4, bottom-top+4)
+	}
+
+	if cfg.ReferencePoint != nil {
+		mark := referenceMark
+		if cfg.MarkerColor.A != 0 {
+			mark = cfg.MarkerColor
+		}
+		px, py := normalize(*cfg.ReferencePoint, view, size)
+		drawMarker(gc, px, py, markerSize(cfg.MarkerSize, size), cfg.MarkerStyle, mark)
+	}
+}
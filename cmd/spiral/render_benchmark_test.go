@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// BenchmarkRenderLinks times renderLinks alone on pre-generated spirals, so
+// render-path changes can be measured without the cost of the sum.
+func BenchmarkRenderLinks(b *testing.B) {
+	// renderLinks logs per worker; keep that out of the timings.
+	prev := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(prev)
+
+	const outputSize = 1024
+	for _, n := range []int{10_000, 100_000, 300_000} {
+		links := testutil.SpiralLinks(n)
+		for _, pointsOnly := range []bool{false, true} {
+			mode := "lines"
+			if pointsOnly {
+				mode = "points"
+			}
+			cfg := RenderConfig{OutputSize: outputSize, PointsOnly: pointsOnly}
+
+			b.Run(fmt.Sprintf("%s_%d", mode, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					renderLinks(links, cfg)
+				}
+				seconds := b.Elapsed().Seconds()
+				b.ReportMetric(float64(outputSize*outputSize*b.N)/seconds, "pixels/s")
+				b.ReportMetric(float64(n*b.N)/seconds, "links/s")
+			})
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"math/cmplx"
+)
+
+const (
+	// residualSpan is how many orders of magnitude below the largest
+	// residual the color ramp reaches; closer links share the hottest color.
+	residualSpan = 3
+	// residualColdest is where on the heat ramp the largest residual
+	// starts, so distant links stay a visible red rather than black.
+	residualColdest = 0.25
+)
+
+// residualScale maps a link's residual |link - target| onto the heat ramp
+// for RenderConfig.ColorByResidual, on a log scale so the slow approach to
+// the final value stays visible: links far from it are red, converged links
+// white.
+type residualScale struct {
+	target         complex128
+	logMin, logMax float64
+}
+
+// newResidualScale fits the ramp to the largest residual among links.
+func newResidualScale(links []complex128, target complex128) *residualScale {
+	var largest float64
+	for _, link := range links {
+		largest = math.Max(largest, cmplx.Abs(link-target))
+	}
+	logMax := math.Log10(math.Max(largest, math.SmallestNonzeroFloat64))
+	return &residualScale{target: target, logMin: logMax - residualSpan, logMax: logMax}
+}
+
+// color returns the stroke color for a segment ending at link.
+func (r *residualScale) color(link complex128) color.RGBA {
+	residual := math.Log10(cmplx.Abs(link - r.target))
+	closeness := 1.0
+	if residual > r.logMin {
+		closeness = (r.logMax - residual) / (r.logMax - r.logMin)
+	}
+	c := heatColor(residualColdest + (1-residualColdest)*closeness)
+	c.A = 128
+	return c
+}
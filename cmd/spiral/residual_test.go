@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+// Test that links far from the final value and converged links get clearly
+// different colors.
+func TestResidualScale_EarlyVsLate(t *testing.T) {
+	s := complex(0.5, 1000)
+	result := calculateSpiralPartialSums(s, ChunkConfig{})
+	links := result.Links
+	scale := newResidualScale(links, result.Total)
+
+	brightness := func(link complex128) int {
+		c := scale.color(link)
+		return int(c.R) + int(c.G) + int(c.B)
+	}
+
+	early := scale.color(links[0])
+	if early.R == 0 || early.G != 0 || early.B != 0 {
+		t.Errorf("got %v for the first link, want a pure red", early)
+	}
+	late := links[len(links)-2]
+	if got, first := brightness(late), brightness(links[0]); got < first+255 {
+		t.Errorf("late link brightness %d is not clearly above the first link's %d", got, first)
+	}
+	if c := scale.color(result.Total); c.R != 255 || c.G != 255 || c.B != 255 {
+		t.Errorf("got %v at the final value, want white", c)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// runChunksPerGoroutine calls compute for every chunk index in [0, numChunks),
+// each in its own goroutine, and waits for all of them.
+func runChunksPerGoroutine(numChunks int, compute func(idx int)) {
+	var wg sync.WaitGroup
+	wg.Add(numChunks)
+	for i := 0; i < numChunks; i++ {
+		go func(idx int) {
+			defer wg.Done()
+			compute(idx)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// runChunksStealing calls compute for every chunk index in [0, numChunks) on
+// a pool of workers. Each worker claims the next index from a shared atomic
+// counter as soon as it finishes its previous chunk, so a worker that drew
+// cheap chunks picks up the slack from one stuck on an expensive chunk.
+func runChunksStealing(numChunks, workers int, compute func(idx int)) {
+	workers = max(1, min(workers, numChunks))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				idx := int(next.Add(1) - 1)
+				if idx >= numChunks {
+					return
+				}
+				compute(idx)
+			}
+		}()
+	}
+	wg.Wait()
+}
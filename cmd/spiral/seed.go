@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// seedNone is the -seed value that prepends no seed link.
+const seedNone = "none"
+
+// parseSeed parses a -seed value: "re,im", or seedNone for no seed link.
+func parseSeed(value string) (seed complex128, prepend bool, err error) {
+	if value == seedNone {
+		return 0, false, nil
+	}
+	re, im, ok := strings.Cut(value, ",")
+	if !ok {
+		return 0, false, fmt.Errorf("invalid -seed %q: want re,im or %s", value, seedNone)
+	}
+	x, err := strconv.ParseFloat(strings.TrimSpace(re), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid -seed real part %q: %v", re, err)
+	}
+	y, err := strconv.ParseFloat(strings.TrimSpace(im), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid -seed imaginary part %q: %v", im, err)
+	}
+	return complex(x, y), true, nil
+}
+
+// seedLinks returns links with seed prepended if it is non-nil. It runs
+// after downsampling, so the seed is never merged into a group, and before
+// rendering, so the view bounds include it.
+func seedLinks(links []complex128, seed *complex128) []complex128 {
+	if seed == nil {
+		return links
+	}
+	return append([]complex128{*seed}, links...)
+}
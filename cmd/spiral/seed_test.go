@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+// Test that the rendered chain starts at the configured seed, that the view
+// bounds take it in, and that -seed none leaves the links untouched.
+func TestSeedLinks(t *testing.T) {
+	links := []complex128{complex(1, 1), complex(2, 3)}
+	seed, prepend, err := parseSeed("-4.5, 2")
+	if err != nil {
+		t.Fatalf("parseSeed: %v", err)
+	}
+	if !prepend {
+		t.Fatal("parseSeed: got no seed to prepend")
+	}
+
+	seeded := seedLinks(links, &seed)
+	if len(seeded) != 3 || seeded[0] != complex(-4.5, 2) {
+		t.Fatalf("got links %v, want the seed -4.5+2i first", seeded)
+	}
+	if view, _ := viewBounds(seeded, RenderConfig{OutputSize: 64}); view.MinX != -4.5 {
+		t.Errorf("got view min X %v, want the seed's -4.5", view.MinX)
+	}
+
+	if _, prepend, err := parseSeed(seedNone); err != nil || prepend {
+		t.Fatalf("parseSeed(%q): got prepend %v, %v; want no seed", seedNone, prepend, err)
+	}
+	if got := seedLinks(links, nil); len(got) != 2 || got[0] != links[0] {
+		t.Errorf("got links %v with -seed none, want %v", got, links)
+	}
+
+	for _, bad := range []string{"", "1", "a,2", "1,b"} {
+		if _, _, err := parseSeed(bad); err == nil {
+			t.Errorf("parseSeed(%q): got no error", bad)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"math/cmplx"
+	"sort"
+)
+
+// Series is a Dirichlet series the compute pipeline can sum into a spiral.
+type Series struct {
+	// Term returns the k-th term of the series at s.
+	Term func(k int, s complex128) complex128
+	// Tail, if set, estimates the terms from k = N on, like zetaTail does
+	// for ζ. Without it the total is the raw partial sum.
+	Tail func(N int, s complex128) complex128
+}
+
+// Built-in series names accepted by -series.
+const (
+	SeriesZeta       = "zeta"
+	SeriesEta        = "eta"
+	SeriesDerivative = "derivative"
+)
+
+// seriesRegistry maps the names accepted by -series to their series. It is
+// not guarded, so register series from init functions, before main runs.
+var seriesRegistry = map[string]Series{
+	SeriesZeta: {
+		Term: func(k int, s complex128) complex128 { return zetaTerm(s)(k) },
+		Tail: func(N int, s complex128) complex128 { return zetaTail(s)(N) },
+	},
+	// The alternating η(s) = Σ (-1)^{k-1} k^{-s}. Its tail is half the
+	// N-th term, which averages the partial sums on either side of it.
+	SeriesEta: {
+		Term: etaTerm,
+		Tail: func(N int, s complex128) complex128 { return etaTerm(N, s) / 2 },
+	},
+	SeriesDerivative: {
+		Term: func(k int, s complex128) complex128 { return zetaDerivativeTerm(s)(k) },
+		Tail: func(N int, s complex128) complex128 { return zetaDerivativeTail(s)(N) },
+	},
+}
+
+// RegisterSeries adds a Dirichlet series with the given term function under
+// name, making it selectable with -series. The spiral's total is its raw
+// partial sum, with no tail correction. It panics if name is empty or
+// already registered.
+func RegisterSeries(name string, termFn func(k int, s complex128) complex128) {
+	if name == "" {
+		panic("RegisterSeries: empty series name")
+	}
+	if _, dup := seriesRegistry[name]; dup {
+		panic(fmt.Sprintf("RegisterSeries: series %q already registered", name))
+	}
+	seriesRegistry[name] = Series{Term: termFn}
+}
+
+// seriesNames returns the names of the registered series in order.
+func seriesNames() []string {
+	names := make([]string, 0, len(seriesRegistry))
+	for name := range seriesRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// etaTerm returns the k-th term (-1)^{k-1} k^{-s} of the η(s) series.
+func etaTerm(k int, s complex128) complex128 {
+	term := cmplx.Pow(complex(float64(k), 0), -s)
+	if k%2 == 0 {
+		return -term
+	}
+	return term
+}
+
+// termFunc returns the term function at s in the form
+// calculateSeriesPartialSums takes.
+func (ser Series) termFunc(s complex128) func(k int) complex128 {
+	return func(k int) complex128 { return ser.Term(k, s) }
+}
+
+// tailFunc returns the tail at s in the form calculateSeriesPartialSums
+// takes, or nil if the series has none.
+func (ser Series) tailFunc(s complex128) func(N int) complex128 {
+	if ser.Tail == nil {
+		return nil
+	}
+	return func(N int) complex128 { return ser.Tail(N, s) }
+}
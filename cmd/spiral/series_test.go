@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math/cmplx"
+	"testing"
+)
+
+// Test that a registered custom series is selectable and sums into a spiral
+// like the built-ins.
+func TestRegisterSeries_Custom(t *testing.T) {
+	// Σ k^{-s} over odd k only, which is (1 - 2^{-s}) ζ(s).
+	const name = "odd-zeta-test"
+	RegisterSeries(name, func(k int, s complex128) complex128 {
+		if k%2 == 0 {
+			return 0
+		}
+		return cmplx.Pow(complex(float64(k), 0), -s)
+	})
+	defer delete(seriesRegistry, name)
+
+	ser, ok := seriesRegistry[name]
+	if !ok {
+		t.Fatalf("series %q not registered; have %v", name, seriesNames())
+	}
+	if ser.tailFunc(2) != nil {
+		t.Error("a registered series should have no tail")
+	}
+
+	// At s = 2 the raw partial sums converge, to (3/4)·π²/6 = π²/8.
+	s := complex(2, 0)
+	result := calculateSeriesPartialSums(s, ser.termFunc(s), ser.tailFunc(s), ChunkConfig{})
+	if len(result.Links) != result.N-1 {
+		t.Errorf("got %d links, want %d", len(result.Links), result.N-1)
+	}
+	if want := 0.75 * Zeta(s); !cmplxEquals(result.Total, want, 1e-2) {
+		t.Errorf("got total %v, want about %v", result.Total, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a duplicate name to panic")
+		}
+	}()
+	RegisterSeries(name, ser.Term)
+}
+
+// Test that the built-in eta series sums to (1 - 2^{1-s}) ζ(s), to the few
+// thousandths its half-term tail reaches at N = MinN.
+func TestSeriesRegistry_Eta(t *testing.T) {
+	s := complex(0.5, 14.134725+3)
+	ser := seriesRegistry[SeriesEta]
+	got := calculateSeriesPartialSums(s, ser.termFunc(s), ser.tailFunc(s), ChunkConfig{}).Total
+	want := (1 - cmplx.Pow(2, 1-s)) * Zeta(s)
+	if !cmplxEquals(got, want, 1e-2) {
+		t.Errorf("eta at %v: got %v, want %v", s, got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"image"
+	"log"
+	"math"
+)
+
+// Renderers accepted by RenderConfig.Renderer.
+const (
+	// RendererDraw2D strokes anti-aliased paths with draw2d and draws the
+	// axis and tick overlay.
+	RendererDraw2D = "draw2d"
+	// RendererSimple plots aliased lines straight into the pixel buffer. It
+	// needs neither draw2d nor a font, so it has no overlay, and it ignores
+	// WidthBySpeed and Ribbon.
+	RendererSimple = "simple"
+)
+
+// validRenderer reports whether name is a known renderer. The empty string
+// selects the default draw2d renderer.
+func validRenderer(name string) bool {
+	return name == "" || name == RendererDraw2D || name == RendererSimple
+}
+
+// simpleAlpha is the coverage each plotted pixel adds, matching the
+// half-opaque white draw2d strokes with.
+const simpleAlpha = 128
+
+// simpleLayer is the single layer renderLinksSimple plots into, adding
+// each plotted pixel when additive is set and compositing it with over
+// otherwise, with the hit counts the heat palette colors it by when hits is
+// non-nil.
+type simpleLayer struct {
+	img      *image.RGBA
+	additive bool
+	hits     *hitBuffer
+}
+
+// renderLinksSimple is renderLinks for RendererSimple: a single layer drawn
+// with drawLine and blended onto the background like the draw2d layers.
+func renderLinksSimple(links []complex128, cfg RenderConfig) *image.RGBA {
+	scale := max(cfg.Supersample, 1)
+	drawSize := cfg.OutputSize * scale
+	view, clip := viewBounds(links, cfg)
+	clipSegments := cfg.ClipSegments && cfg.Bounds != nil
+	if clipSegments {
+		clip = false
+	}
+	chains := [][]complex128{links}
+	if cfg.Mirror {
+		chains = append(chains, mirrorLinks(links))
+		if cfg.Bounds == nil {
+			view = mirrorView(view)
+		}
+	}
+
+	layer := simpleLayer{img: image.NewRGBA(image.Rect(0, 0, drawSize, drawSize)), additive: cfg.Blend != BlendOver}
+	if cfg.Palette == PaletteHeat {
+		layer.hits = newHitBuffer(cfg.OutputSize, scale)
+	}
+	for _, chain := range chains {
+		var prevX, prevY float64
+		for i, link := range chain {
+			fx, fy := framePoint(link, view, drawSize, clip)
+			if cfg.PointsOnly {
+				if clipSegments && !inFrame(fx, fy, float64(drawSize)) {
+					continue
+				}
+				x, y := int(math.Round(fx)), int(math.Round(fy))
+				for dy := -scale; dy <= scale; dy++ {
+					for dx := -scale; dx <= scale; dx++ {
+						layer.plot(x+dx, y+dy, 255)
+					}
+				}
+			} else if i > 0 {
+				x0, y0, x1, y1, visible := prevX, prevY, fx, fy, true
+				if clipSegments {
+					x0, y0, x1, y1, visible = clipSegment(prevX, prevY, fx, fy, float64(drawSize))
+				}
+				if visible {
+					layer.drawLine(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1)), simpleAlpha)
+				}
+			}
+			prevX, prevY = fx, fy
+		}
+		if !cfg.PointsOnly && len(chain) > 0 {
+			// drawLine leaves off each segment's end point, so add the last
+			// one; plot drops it if it is outside the frame.
+			layer.plot(int(math.Round(prevX)), int(math.Round(prevY)), simpleAlpha)
+		}
+	}
+	log.Printf("Simple renderer drew %d links\n", len(links))
+
+	img := layer.img
+	if scale > 1 {
+		img = boxDownsample(img, scale)
+	}
+	return blendLayers([]*image.RGBA{img}, layer.hits, cfg)
+}
+
+// drawLine plots the pixels of the segment from (x0, y0) to (x1, y1) with
+// Bresenham's algorithm, plotting white at the given alpha at each. The
+// shared end point of consecutive segments is plotted only once.
+func (l simpleLayer) drawLine(x0, y0, x1, y1 int, alpha uint8) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		l.plot(x0, y0, alpha)
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// plot adds or composites premultiplied white at the given alpha onto the
+// pixel at (x, y) and counts it as a hit, ignoring points outside the
+// layer.
+func (l simpleLayer) plot(x, y int, alpha uint8) {
+	if !(image.Point{x, y}.In(l.img.Rect)) {
+		return
+	}
+	if l.hits != nil {
+		l.hits.add(x, y, uint32(alpha)*hitUnit/255)
+	}
+	offset := l.img.PixOffset(x, y)
+	for c := 0; c < 4; c++ {
+		dst := uint32(l.img.Pix[offset+c])
+		if l.additive {
+			l.img.Pix[offset+c] = uint8(min(dst+uint32(alpha), 255))
+		} else {
+			l.img.Pix[offset+c] = uint8(uint32(alpha) + dst*(255-uint32(alpha))/255)
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
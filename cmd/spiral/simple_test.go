@@ -0,0 +1,63 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that the simple renderer lights nearly the same pixels as draw2d.
+func TestRenderLinksSimple_MatchesDraw2D(t *testing.T) {
+	links := testutil.SpiralLinks(2000)
+	outputSize := 128
+
+	litSet := func(img *image.RGBA) map[image.Point]bool {
+		lit := make(map[image.Point]bool)
+		for y := 0; y < outputSize; y++ {
+			for x := 0; x < outputSize; x++ {
+				if img.RGBAAt(x, y).R > 40 {
+					lit[image.Point{x, y}] = true
+				}
+			}
+		}
+		return lit
+	}
+	nearby := func(lit map[image.Point]bool, p image.Point) bool {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if lit[p.Add(image.Point{dx, dy})] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, pointsOnly := range []bool{false, true} {
+		full := litSet(renderLinks(links, RenderConfig{OutputSize: outputSize, PointsOnly: pointsOnly}))
+		simple := litSet(renderLinks(links, RenderConfig{OutputSize: outputSize, PointsOnly: pointsOnly, Renderer: RendererSimple}))
+		if len(simple) == 0 {
+			t.Fatalf("points=%v: simple renderer drew nothing", pointsOnly)
+		}
+
+		// Every simple pixel should be on or next to a draw2d one, and the
+		// other way around: the two differ only by anti-aliasing.
+		miss := 0
+		for p := range simple {
+			if !nearby(full, p) {
+				miss++
+			}
+		}
+		for p := range full {
+			if !nearby(simple, p) {
+				miss++
+			}
+		}
+		t.Logf("points=%v: %d simple, %d draw2d lit pixels, %d unmatched", pointsOnly, len(simple), len(full), miss)
+		if miss > (len(simple)+len(full))/20 {
+			t.Errorf("points=%v: %d of %d lit pixels have no counterpart in the other render",
+				pointsOnly, miss, len(simple)+len(full))
+		}
+	}
+}
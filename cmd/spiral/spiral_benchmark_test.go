@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"math/cmplx"
+	"runtime"
+	"sync"
 	"testing"
 )
 
@@ -15,31 +17,150 @@ func BenchmarkCalculateSpiralPartialSums(b *testing.B) {
 
 	for _, numChunks := range chunkSizes {
 		b.Run(fmt.Sprintf("chunks=%d", numChunks), func(b *testing.B) {
-			// Calculate N
-			N := int(cmplx.Abs(s))
-			if N < MinN {
-				N = MinN
-			} else if N > MaxN {
-				N = MaxN
-			}
-
-			// Override the dynamic chunk size for this test
-			originalChunkSize := ChunkSize
-			ChunkSize = (N + numChunks - 1) / numChunks
-
+			// (N-1)/numChunks + 1 rounds up without forming N + numChunks,
+			// which would overflow for N near the int limit.
+			N := termCount(s, 0)
+			chunks := ChunkConfig{Size: (N-1)/numChunks + 1}
 			b.ResetTimer()
 			b.ReportAllocs()
 
 			for i := 0; i < b.N; i++ {
-				result, links := calculateSpiralPartialSums(s)
+				result := calculateSpiralPartialSums(s, chunks)
 				// Prevent compiler optimization
-				if real(result) == 0 && len(links) == 0 {
+				if real(result.Total) == 0 && len(result.Links) == 0 {
 					b.Fatal("unexpected zero result")
 				}
 			}
-
-			// Restore original chunk size
-			ChunkSize = originalChunkSize
 		})
 	}
 }
+
+// computePartialSumComplex64 mirrors computePartialSumWithLinks but accumulates
+// in complex64, the precision used by the distributed path's messages.
+func computePartialSumComplex64(start, end int, s complex128) complex64 {
+	var partialSum complex64
+	for k := start; k < end; k++ {
+		partialSum += complex64(cmplx.Pow(complex(float64(k), 0), -s))
+	}
+	return partialSum
+}
+
+// computePartialSumComplex128 is the lean float64 reference for the same range.
+func computePartialSumComplex128(start, end int, s complex128) complex128 {
+	var partialSum complex128
+	for k := start; k < end; k++ {
+		partialSum += cmplx.Pow(complex(float64(k), 0), -s)
+	}
+	return partialSum
+}
+
+func BenchmarkPartialSumPrecision(b *testing.B) {
+	s := complex(0.5, 6_300_000.0)
+	start, end := 1, 200_000
+
+	reference := computePartialSumComplex128(start, end, s)
+
+	b.Run("complex128", func(b *testing.B) {
+		var sum complex128
+		for i := 0; i < b.N; i++ {
+			sum = computePartialSumComplex128(start, end, s)
+		}
+		b.ReportMetric(cmplx.Abs(sum-reference), "abs_error")
+	})
+
+	b.Run("complex64", func(b *testing.B) {
+		var sum complex64
+		for i := 0; i < b.N; i++ {
+			sum = computePartialSumComplex64(start, end, s)
+		}
+		b.ReportMetric(cmplx.Abs(complex128(sum)-reference), "abs_error")
+	})
+}
+
+// Test that complex64 accumulation drifts far enough from complex128 to matter
+// for a plotted spiral, which is why sums should be carried in float64.
+func TestPartialSumPrecision(t *testing.T) {
+	s := complex(0.5, 6_300_000.0)
+	start, end := 1, 200_000
+
+	sum64 := computePartialSumComplex64(start, end, s)
+	sum128 := computePartialSumComplex128(start, end, s)
+
+	absError := cmplx.Abs(complex128(sum64) - sum128)
+	t.Logf("complex64 vs complex128 abs_error over %d terms: %e", end-start, absError)
+
+	// The spiral lives at roughly unit scale, so float32 rounding alone is about
+	// 1e-7 per step. An accumulated error ten times that is visible when the
+	// renderer zooms into the tail.
+	const threshold = 1e-6
+	if absError < threshold {
+		t.Errorf("complex64 error %e is below %e; float64 fields would not be justified", absError, threshold)
+	}
+}
+
+// runChunksStatic gives each worker one contiguous block of chunk indices,
+// the baseline that work stealing is measured against.
+func runChunksStatic(numChunks, workers int, compute func(idx int)) {
+	per := (numChunks + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*per, min((w+1)*per, numChunks)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := start; idx < end; idx++ {
+				compute(idx)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkChunkScheduling compares a static partition with work stealing on
+// a skewed workload where the first chunks cost far more than the rest, as
+// when -start-k or an adaptive N leaves chunks of uneven length.
+func BenchmarkChunkScheduling(b *testing.B) {
+	const numChunks = 256
+	workers := max(runtime.NumCPU(), 4)
+	s := complex(0.5, 1000)
+	chunkLen := func(idx int) int {
+		if idx < numChunks/workers {
+			return 20000
+		}
+		return 500
+	}
+	compute := func(idx int) {
+		computePartialSumWithLinks(1, 1+chunkLen(idx), s)
+	}
+
+	b.Run("static", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			runChunksStatic(numChunks, workers, compute)
+		}
+	})
+	b.Run("stealing", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			runChunksStealing(numChunks, workers, compute)
+		}
+	})
+}
+
+// BenchmarkComputePartialSum compares a chunk with and without link
+// tracking; the sum-only path should report zero allocations.
+func BenchmarkComputePartialSum(b *testing.B) {
+	s := complex(0.5, 6_300_000.0)
+	const end = 100_000
+
+	b.Run("links", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			computePartialSumWithLinks(1, end, s)
+		}
+	})
+	b.Run("sum-only", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			computePartialSum(1, end, s)
+		}
+	})
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"math/big"
+	"math/cmplx"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Test that disabling the link correction only changes the final link, and
+// by exactly the Euler-Maclaurin correction terms.
+func TestCalculateSpiralPartialSums_NoCorrection(t *testing.T) {
+	s := complex(0.5, 14.134725)
+	defer func(orig bool) { CorrectFinalLink = orig }(CorrectFinalLink)
+
+	CorrectFinalLink = true
+	result := calculateSpiralPartialSums(s, ChunkConfig{})
+	correctedTotal, corrected := result.Total, result.Links
+	CorrectFinalLink = false
+	result = calculateSpiralPartialSums(s, ChunkConfig{})
+	rawTotal, raw := result.Total, result.Links
+
+	if correctedTotal != rawTotal {
+		t.Errorf("total changed: got %v, want %v", rawTotal, correctedTotal)
+	}
+	if len(corrected) != len(raw) {
+		t.Fatalf("got %d raw links, want %d", len(raw), len(corrected))
+	}
+	for i := 0; i < len(raw)-1; i++ {
+		if raw[i] != corrected[i] {
+			t.Fatalf("link %d differs: got %v, want %v", i, raw[i], corrected[i])
+		}
+	}
+
+	N := MinN
+	term1 := cmplx.Pow(complex(float64(N), 0), 1-s) / (s - 1)
+	term2 := 0.5 * cmplx.Pow(complex(float64(N), 0), -s)
+	last := len(raw) - 1
+	if diff := corrected[last] - raw[last]; !cmplxEquals(diff, term1+term2, 1e-12) {
+		t.Errorf("final link differs by %v, want %v", diff, term1+term2)
+	}
+}
+
+// Test that the generalized series path matches the zeta-specific one and
+// computes ζ'(2) with the derivative term.
+func TestComputeSeriesWithLinks(t *testing.T) {
+	s := complex(0.5, 14.134725)
+	wantSum, wantLinks := computePartialSumWithLinks(1, 500, s)
+	gotSum, gotLinks := computeSeriesWithLinks(1, 500, zetaTerm(s), SumAscending)
+	if gotSum != wantSum || len(gotLinks) != len(wantLinks) {
+		t.Fatalf("got sum %v with %d links, want %v with %d", gotSum, len(gotLinks), wantSum, len(wantLinks))
+	}
+	for i := range wantLinks {
+		if gotLinks[i] != wantLinks[i] {
+			t.Fatalf("link %d: got %v, want %v", i, gotLinks[i], wantLinks[i])
+		}
+	}
+
+	// Sum k < N directly, then add the Euler-Maclaurin tail
+	// ∫_N^∞ -log(x)/x² dx - log(N)/(2N²) for the remaining terms.
+	N := 10_000
+	partial, _ := computeSeriesWithLinks(1, N, zetaDerivativeTerm(2), SumAscending)
+	fN := float64(N)
+	tail := -(math.Log(fN)+1)/fN - math.Log(fN)/(2*fN*fN)
+	got := real(partial) + tail
+
+	const zetaPrime2 = -0.93754825431584375370
+	if !floatEquals(got, zetaPrime2, 1e-8) {
+		t.Errorf("ζ'(2): got %.12f, want %.12f", got, zetaPrime2)
+	}
+	if imag(partial) != 0 {
+		t.Errorf("ζ'(2) should be real, got imaginary part %g", imag(partial))
+	}
+}
+
+// Test that the chunk dump records contiguous term ranges and offsets that
+// accumulate each chunk's partial sum in order.
+func TestCalculateSpiralPartialSums_DumpChunks(t *testing.T) {
+	var buf bytes.Buffer
+	s := complex(0.5, 14.134725)
+	links := calculateSpiralPartialSums(s, ChunkConfig{Size: 30, Dump: &buf}).Links
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse dump: %v", err)
+	}
+	if len(rows) < 3 {
+		t.Fatalf("got %d rows, want a header and several chunks", len(rows))
+	}
+
+	parse := func(field string) float64 {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			t.Fatalf("bad field %q: %v", field, err)
+		}
+		return v
+	}
+
+	prevEnd := 1
+	var expectedOffset complex128
+	for _, row := range rows[1:] {
+		start, end := int(parse(row[1])), int(parse(row[2]))
+		partial := complex(parse(row[3]), parse(row[4]))
+		offset := complex(parse(row[5]), parse(row[6]))
+
+		if start != prevEnd {
+			t.Errorf("chunk %s starts at %d, want %d", row[0], start, prevEnd)
+		}
+		if !cmplxEquals(offset, expectedOffset, 1e-12) {
+			t.Errorf("chunk %s offset %v, want %v", row[0], offset, expectedOffset)
+		}
+		// The chunk's last link is its offset plus its own partial sum.
+		if last := links[end-2]; end < MinN && !cmplxEquals(last, offset+partial, 1e-12) {
+			t.Errorf("chunk %s last link %v, want %v", row[0], last, offset+partial)
+		}
+		prevEnd = end
+		expectedOffset += partial
+	}
+	if prevEnd != MinN {
+		t.Errorf("chunks end at %d, want %d", prevEnd, MinN)
+	}
+}
+
+// Test that links start at StartK while the total still includes the prefix.
+func TestCalculateSpiralPartialSums_StartK(t *testing.T) {
+	s := complex(0.5, 14.134725)
+	const startK = 10
+
+	result := calculateSpiralPartialSums(s, ChunkConfig{})
+	fullTotal, fullLinks := result.Total, result.Links
+
+	result = calculateSpiralPartialSums(s, ChunkConfig{StartK: startK})
+	total, links := result.Total, result.Links
+
+	if !cmplxEquals(total, fullTotal, 1e-12) {
+		t.Errorf("total changed: got %v, want %v", total, fullTotal)
+	}
+	if want := len(fullLinks) - (startK - 1); len(links) != want {
+		t.Fatalf("got %d links, want %d", len(links), want)
+	}
+
+	// The first link is the partial sum through k = startK.
+	var want complex128
+	for k := 1; k <= startK; k++ {
+		want += cmplx.Pow(complex(float64(k), 0), -s)
+	}
+	if !cmplxEquals(links[0], want, 1e-12) {
+		t.Errorf("first link: got %v, want %v", links[0], want)
+	}
+	for i := range links {
+		if !cmplxEquals(links[i], fullLinks[i+startK-1], 1e-12) {
+			t.Fatalf("link %d: got %v, want %v", i, links[i], fullLinks[i+startK-1])
+		}
+	}
+}
+
+// Test that the work-stealing scheduler produces exactly the same sum and
+// links as one goroutine per chunk.
+func TestCalculateSpiralPartialSums_WorkStealing(t *testing.T) {
+	s := complex(0.5, 5000)
+
+	result := calculateSpiralPartialSums(s, ChunkConfig{})
+	wantTotal, wantLinks := result.Total, result.Links
+
+	result = calculateSpiralPartialSums(s, ChunkConfig{WorkStealing: true})
+	total, links := result.Total, result.Links
+
+	if total != wantTotal {
+		t.Errorf("total: got %v, want %v", total, wantTotal)
+	}
+	if len(links) != len(wantLinks) {
+		t.Fatalf("got %d links, want %d", len(links), len(wantLinks))
+	}
+	for i := range links {
+		if links[i] != wantLinks[i] {
+			t.Fatalf("link %d: got %v, want %v", i, links[i], wantLinks[i])
+		}
+	}
+}
+
+// Test the term boundary: the direct sum stops at k = N-1 and the
+// Euler-Maclaurin ½N^{-s} term stands in for k = N, so ζ(2) comes out to
+// within the O(N^{-3}) truncation error. Summing through k = N instead would
+// be off by ½N^{-2} = 5e-5.
+func TestCalculateSpiralPartialSums_TermBoundary(t *testing.T) {
+	s := complex(2, 0)
+	N := termCount(s, 0)
+
+	// Reference: Σ_{k=1}^{N-1} k^{-s} + N^{1-s}/(s-1) + ½N^{-s}.
+	var direct complex128
+	for k := 1; k < N; k++ {
+		direct += cmplx.Pow(complex(float64(k), 0), -s)
+	}
+	fN := complex(float64(N), 0)
+	reference := direct + cmplx.Pow(fN, 1-s)/(s-1) + 0.5*cmplx.Pow(fN, -s)
+
+	result := calculateSpiralPartialSums(s, ChunkConfig{})
+	total, links := result.Total, result.Links
+	if !cmplxEquals(total, reference, 1e-12) {
+		t.Errorf("got total %v, want reference %v", total, reference)
+	}
+	if want := complex(math.Pi*math.Pi/6, 0); !cmplxEquals(total, want, 1e-6) {
+		t.Errorf("got ζ(2) ≈ %v, want %v", total, want)
+	}
+	if len(links) != N-1 {
+		t.Errorf("got %d links, want one per term k = 1..%d", len(links), N-1)
+	}
+}
+
+// Test that small chunks still cover every term instead of stopping after a
+// fixed number of chunks.
+func TestCalculateSpiralPartialSums_SmallChunksCoverAllTerms(t *testing.T) {
+	s := complex(0.5, 5000)
+	N := termCount(s, 0)
+
+	result := calculateSpiralPartialSums(s, ChunkConfig{Size: N})
+	wantTotal, wantLinks := result.Total, result.Links
+
+	result = calculateSpiralPartialSums(s, ChunkConfig{Size: 3})
+	total, links := result.Total, result.Links
+
+	if len(links) != N-1 || len(wantLinks) != N-1 {
+		t.Fatalf("got %d and %d links, want %d", len(links), len(wantLinks), N-1)
+	}
+	if !cmplxEquals(total, wantTotal, 1e-9) {
+		t.Errorf("got total %v with small chunks, want %v", total, wantTotal)
+	}
+}
+
+// Test that the result metadata is consistent with s and the links.
+func TestCalculateSpiralPartialSums_ResultFields(t *testing.T) {
+	s := complex(0.5, 500)
+	result := calculateSpiralPartialSums(s, ChunkConfig{})
+
+	if result.N != termCount(s, 0) {
+		t.Errorf("got N %d, want %d", result.N, termCount(s, 0))
+	}
+	if len(result.Links) != result.N-1 {
+		t.Errorf("got %d links, want N-1 = %d", len(result.Links), result.N-1)
+	}
+
+	fN := complex(float64(result.N), 0)
+	wantCorrection := cmplx.Pow(fN, 1-s)/(s-1) + 0.5*cmplx.Pow(fN, -s)
+	if !cmplxEquals(result.Correction, wantCorrection, 1e-12) {
+		t.Errorf("got correction %v, want %v", result.Correction, wantCorrection)
+	}
+
+	// With the correction on the final link, the last link is the total.
+	if last := result.Links[len(result.Links)-1]; !cmplxEquals(last, result.Total, 1e-12) {
+		t.Errorf("last link %v does not match total %v", last, result.Total)
+	}
+	if result.Elapsed <= 0 {
+		t.Errorf("got elapsed %v, want a positive duration", result.Elapsed)
+	}
+}
+
+// Test that the derivative series with its differentiated tail converges to
+// ζ'(2) = -0.93754825431584...
+func TestCalculateSeriesPartialSums_Derivative(t *testing.T) {
+	s := complex(2, 0)
+	result := calculateSeriesPartialSums(s, zetaDerivativeTerm(s), zetaDerivativeTail(s), ChunkConfig{})
+
+	want := complex(-0.9375482543158437, 0)
+	if !cmplxEquals(result.Total, want, 1e-5) {
+		t.Errorf("got ζ'(2) ≈ %v, want %v", result.Total, want)
+	}
+	if last := result.Links[len(result.Links)-1]; !cmplxEquals(last, result.Total, 1e-12) {
+		t.Errorf("final link %v does not match total %v", last, result.Total)
+	}
+}
+
+// Test that the sum-only path matches the link-tracking one exactly.
+func TestCalculateSpiralSum_MatchesLinks(t *testing.T) {
+	s := complex(0.5, 5000)
+
+	want, _ := computePartialSumWithLinks(1, 500, s)
+	if got := computePartialSum(1, 500, s); got != want {
+		t.Errorf("chunk sum: got %v, want %v", got, want)
+	}
+
+	for _, chunkSize := range []int{termCount(s, 0), 7} {
+		chunks := ChunkConfig{Size: chunkSize}
+		if got, want := calculateSpiralSum(s, chunks), calculateSpiralPartialSums(s, chunks).Total; got != want {
+			t.Errorf("Size=%d: got %v, want %v", chunkSize, got, want)
+		}
+	}
+}
+
+// Test that float32 link storage tracks the float64 links to single
+// precision and frames the same view.
+func TestCalculateSpiralPartialSums_Float32(t *testing.T) {
+	s := complex(0.5, 5000)
+
+	wide := calculateSpiralPartialSums(s, ChunkConfig{})
+	narrow := calculateSpiralPartialSums(s, ChunkConfig{Precision: PrecisionFloat32})
+
+	if narrow.Links != nil {
+		t.Fatalf("got %d float64 links in float32 mode, want none", len(narrow.Links))
+	}
+	if len(narrow.Links32) != len(wide.Links) {
+		t.Fatalf("got %d float32 links, want %d", len(narrow.Links32), len(wide.Links))
+	}
+	if narrow.Total != wide.Total {
+		t.Errorf("got total %v, want the float64 total %v", narrow.Total, wide.Total)
+	}
+	for i, link := range narrow.Links32 {
+		if !cmplxEquals(complex128(link), wide.Links[i], 1e-5) {
+			t.Fatalf("link %d: got %v, want %v", i, link, wide.Links[i])
+		}
+	}
+
+	minX, maxX, minY, maxY := computeBounds(narrow.Links32)
+	wantMinX, wantMaxX, wantMinY, wantMaxY := computeBounds(wide.Links)
+	for _, pair := range [][2]float64{{minX, wantMinX}, {maxX, wantMaxX}, {minY, wantMinY}, {maxY, wantMaxY}} {
+		if !floatEquals(pair[0], pair[1], 1e-5) {
+			t.Errorf("got bounds X [%f, %f] Y [%f, %f], want X [%f, %f] Y [%f, %f]",
+				minX, maxX, minY, maxY, wantMinX, wantMaxX, wantMinY, wantMaxY)
+			break
+		}
+	}
+
+	got := downsampleComplex(narrow.Links32, 256, 0.5, DownsampleOptions{}, false)
+	want := downsampleComplex(wide.Links, 256, 0.5, DownsampleOptions{}, false)
+	if diff := math.Abs(float64(len(got) - len(want))); diff > 0.01*float64(len(want)) {
+		t.Errorf("downsampled to %d points from float32, want about %d", len(got), len(want))
+	}
+}
+
+// Test that the chunked total near the first zero agrees with the serial
+// Euler-Maclaurin sum for the same N whatever the chunking, and that chunks
+// are chained in index order rather than completion order, so repeated runs
+// under either scheduler give bit-identical totals.
+func TestCalculateSpiralPartialSums_MatchesSerialSum(t *testing.T) {
+	s := complex(0.5, 14.135)
+	N := termCount(s, 0)
+	want := eulerMaclaurinSum(s, N)
+
+	for _, chunkSize := range []int{1, 7, N - 1, N, 4 * N} {
+		var first complex128
+		for run := 0; run < 4; run++ {
+			result := calculateSpiralPartialSums(s, ChunkConfig{Size: chunkSize, WorkStealing: run%2 == 1})
+			if run == 0 {
+				first = result.Total
+				if !cmplxEquals(result.Total, want, 1e-12) {
+					t.Errorf("Size=%d: got %v, want serial sum %v", chunkSize, result.Total, want)
+				}
+			} else if result.Total != first {
+				t.Errorf("Size=%d run %d: got %v, want the identical %v", chunkSize, run, result.Total, first)
+			}
+			if len(result.Links) != N-1 {
+				t.Errorf("Size=%d: got %d links, want one per k in [1, N) = %d", chunkSize, len(result.Links), N-1)
+			}
+		}
+	}
+}
+
+// Test that a computation panicking under a custom chunk size leaves later
+// computations with the default chunking unaffected.
+func TestCalculateSeriesPartialSums_PanicDoesNotLeakChunking(t *testing.T) {
+	s := complex(0.5, 14.134725)
+	want := calculateSpiralPartialSums(s, ChunkConfig{})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the computation to panic")
+			}
+		}()
+		// The tail runs on the calling goroutine once the chunks are in,
+		// so its panic reaches the recover above.
+		calculateSeriesPartialSums(s, zetaTerm(s), func(N int) complex128 {
+			panic("tail failed")
+		}, ChunkConfig{Size: 7})
+	}()
+	var dump bytes.Buffer
+	got := calculateSpiralPartialSums(s, ChunkConfig{Dump: &dump})
+	if got.Total != want.Total || len(got.Links) != len(want.Links) {
+		t.Errorf("got total %v over %d links after the panic, want %v over %d",
+			got.Total, len(got.Links), want.Total, len(want.Links))
+	}
+	// One header and one row per default-sized chunk, not per 7 terms.
+	if rows := strings.Count(dump.String(), "\n") - 1; rows != (got.N-2)/defaultChunkSize+1 {
+		t.Errorf("got %d chunks after the panic, want %d of the default size %d", rows, (got.N-2)/defaultChunkSize+1, defaultChunkSize)
+	}
+}
+
+// Test that descending-order summation of ζ(2)'s terms at large N lands
+// closer to the exact sum of those same float64 terms than ascending order,
+// while the links still chain to the total.
+func TestCalculateSpiralPartialSums_SumOrder(t *testing.T) {
+	s := complex(2, 0)
+	const terms = 1 << 21
+
+	// Sum the terms exactly; they are all real and positive.
+	exact := new(big.Float).SetPrec(256)
+	term := zetaTerm(s)
+	for k := 1; k < terms; k++ {
+		exact.Add(exact, new(big.Float).SetFloat64(real(term(k))))
+	}
+	tail := zetaTail(s)(terms)
+	exactTotal, _ := new(big.Float).Add(exact, new(big.Float).SetFloat64(real(tail))).Float64()
+
+	var errs [2]float64
+	for i, order := range []string{SumAscending, SumDescending} {
+		chunks := ChunkConfig{Size: terms / 4, Order: order, Terms: terms}
+		result := calculateSpiralPartialSums(s, chunks)
+		errs[i] = math.Abs(real(result.Total) - exactTotal)
+
+		last := result.Links[len(result.Links)-1]
+		if !cmplxEquals(last, result.Total, 1e-14) {
+			t.Errorf("%s: got last link %v, want the total %v", order, last, result.Total)
+		}
+		if got := calculateSpiralSum(s, chunks); got != result.Total {
+			t.Errorf("%s: got sum %v, want the identical linked total %v", order, got, result.Total)
+		}
+	}
+	t.Logf("error against the exact sum: ascending %e, descending %e", errs[0], errs[1])
+	if errs[1] >= errs[0] {
+		t.Errorf("got descending error %e, want it below the ascending %e", errs[1], errs[0])
+	}
+}
+
+// Test that a chunk size below 1, from a bad setting or a tiny MaxN, falls
+// back to the default rather than panicking on a division by zero, and
+// still sums every term.
+func TestCalculateSpiralPartialSums_NonPositiveChunkSize(t *testing.T) {
+	s := complex(0.5, 14.135)
+	want := calculateSpiralPartialSums(s, ChunkConfig{})
+
+	for _, size := range []int{0, -5} {
+		got := calculateSpiralPartialSums(s, ChunkConfig{Size: size})
+		if got.Total != want.Total || len(got.Links) != len(want.Links) {
+			t.Errorf("Size=%d: got total %v over %d links, want %v over %d",
+				size, got.Total, len(got.Links), want.Total, len(want.Links))
+		}
+	}
+
+	defer func(orig int) { MaxN = orig }(MaxN)
+	MaxN = 0
+	if size := calculateDefaultChunkSize(); size < 1 {
+		t.Errorf("got default chunk size %d with MaxN 0, want at least 1", size)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"math/cmplx"
+	"slices"
+)
+
+// statsCoverageSize is the nominal render size -stats-only measures pixel
+// coverage at, so results are comparable whatever -size is.
+const statsCoverageSize = 1024
+
+// jsonComplex is a complex value as a JSON object.
+type jsonComplex struct {
+	Re float64 `json:"re"`
+	Im float64 `json:"im"`
+}
+
+// coverageStats is CoverageStats as JSON.
+type coverageStats struct {
+	Size     int     `json:"size"`
+	Pixels   int     `json:"pixels"`
+	Fraction float64 `json:"fraction"`
+}
+
+// stepDecade counts the steps whose length lies in [10^Exponent,
+// 10^(Exponent+1)).
+type stepDecade struct {
+	Exponent int `json:"exponent"`
+	Count    int `json:"count"`
+}
+
+// stepStats summarizes the lengths of the steps between consecutive links.
+// Zero-length steps count towards Count but have no decade.
+type stepStats struct {
+	Count   int          `json:"count"`
+	Min     float64      `json:"min"`
+	Median  float64      `json:"median"`
+	Mean    float64      `json:"mean"`
+	Max     float64      `json:"max"`
+	Decades []stepDecade `json:"decades"`
+}
+
+// spiralStats is the JSON document -stats-only prints.
+type spiralStats struct {
+	S            jsonComplex   `json:"s"`
+	Zeta         jsonComplex   `json:"zeta"`
+	Links        int           `json:"links"`
+	WindingRad   float64       `json:"windingRad"`
+	WindingTurns float64       `json:"windingTurns"`
+	SignedArea   float64       `json:"signedArea"`
+	Bounds       threeJSBounds `json:"bounds"`
+	Coverage     coverageStats `json:"coverage"`
+	Steps        stepStats     `json:"steps"`
+}
+
+// newStepStats summarizes the step lengths of links.
+func newStepStats[T linkValue](links []T) stepStats {
+	if len(links) < 2 {
+		return stepStats{Decades: []stepDecade{}}
+	}
+	lengths := make([]float64, len(links)-1)
+	var sum float64
+	for i := range lengths {
+		lengths[i] = cmplx.Abs(complex128(links[i+1]) - complex128(links[i]))
+		sum += lengths[i]
+	}
+	slices.Sort(lengths)
+
+	stats := stepStats{
+		Count:   len(lengths),
+		Min:     lengths[0],
+		Median:  lengths[len(lengths)/2],
+		Mean:    sum / float64(len(lengths)),
+		Max:     lengths[len(lengths)-1],
+		Decades: []stepDecade{},
+	}
+	// lengths is sorted, so each decade's steps are contiguous.
+	for _, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		exp := int(math.Floor(math.Log10(l)))
+		if n := len(stats.Decades); n > 0 && stats.Decades[n-1].Exponent == exp {
+			stats.Decades[n-1].Count++
+		} else {
+			stats.Decades = append(stats.Decades, stepDecade{Exponent: exp, Count: 1})
+		}
+	}
+	return stats
+}
+
+// computeStats gathers the metrics -stats-only reports for the chain of
+// links computed at s, whose value is zeta.
+func computeStats[T linkValue](s, zeta complex128, links []T) spiralStats {
+	winding := TotalWinding(links)
+	stats := spiralStats{
+		S:            jsonComplex{real(s), imag(s)},
+		Zeta:         jsonComplex{real(zeta), imag(zeta)},
+		Links:        len(links),
+		WindingRad:   winding,
+		WindingTurns: winding / (2 * math.Pi),
+		SignedArea:   SignedArea(links),
+		Steps:        newStepStats(links),
+	}
+	if len(links) > 0 {
+		b := boundsOf(links)
+		stats.Bounds = threeJSBounds{MinX: b.MinX, MaxX: b.MaxX, MinY: b.MinY, MaxY: b.MaxY}
+	}
+	pixels, _, fraction := CoverageStats(links, statsCoverageSize)
+	stats.Coverage = coverageStats{Size: statsCoverageSize, Pixels: pixels, Fraction: fraction}
+	return stats
+}
+
+// writeStats writes stats to w as indented JSON.
+func writeStats(w io.Writer, stats spiralStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stats)
+}
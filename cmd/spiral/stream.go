@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+)
+
+// RenderLinksStream draws links as they arrive on ch until it is closed, so
+// rendering can overlap computing them. A stream's extent isn't known until
+// it ends, so cfg.Bounds must be set; links outside it are clamped to the
+// frame edge. Options that need the whole chain up front (ClipPercentile,
+// WidthBySpeed, Ribbon, Fade, DensityAlpha and ColorByResidual),
+// ClipSegments, Mirror, Quiver and RendererSimple are rejected, and nothing
+// is read from ch when an error is returned.
+func RenderLinksStream(ch <-chan complex128, cfg RenderConfig) (*image.RGBA, error) {
+	switch {
+	case cfg.Bounds == nil:
+		return nil, errors.New("streaming render needs bounds in the render config")
+	case cfg.ClipPercentile > 0 && cfg.ClipPercentile < 100:
+		return nil, errors.New("streaming render does not support percentile clipping")
+	case cfg.WidthBySpeed || cfg.Ribbon:
+		return nil, errors.New("streaming render does not support width-by-speed or ribbon strokes")
+	case cfg.ClipSegments:
+		return nil, errors.New("streaming render does not support segment clipping")
+	case cfg.Fade > 0:
+		return nil, errors.New("streaming render does not support fading")
+	case cfg.Mirror:
+		return nil, errors.New("streaming render does not support mirroring")
+	case cfg.DensityAlpha:
+		return nil, errors.New("streaming render does not support density alpha")
+	case cfg.ColorByResidual:
+		return nil, errors.New("streaming render does not support residual coloring")
+	case cfg.Quiver > 0:
+		return nil, errors.New("streaming render does not support quiver arrows")
+	case cfg.Renderer == RendererSimple:
+		return nil, errors.New("streaming render does not support the simple renderer")
+	}
+	b := *cfg.Bounds
+	scale := max(cfg.Supersample, 1)
+	drawSize := cfg.OutputSize * scale
+
+	// A single transparent layer, drawn the same way as one renderLinks worker.
+	img := image.NewRGBA(image.Rect(0, 0, drawSize, drawSize))
+	var hits *hitBuffer
+	if cfg.Palette == PaletteHeat {
+		hits = newHitBuffer(cfg.OutputSize, scale)
+	}
+	painter := newLayerPainter(img, cfg.Blend, hits)
+	gc := draw2dimg.NewGraphicContextWithPainter(img, painter)
+	strokes := newSegmentStroker(painter, drawSize)
+	gc.SetStrokeColor(color.RGBA{255, 255, 255, 255})
+	gc.SetFillColor(color.RGBA{255, 255, 255, 255})
+	gc.SetLineWidth(0.5 * float64(scale))
+
+	count := 0
+	var prevX, prevY float64
+	for link := range ch {
+		x, y := framePoint(link, b, drawSize, true)
+		switch {
+		case cfg.PointsOnly:
+			radius := float64(scale)
+			gc.BeginPath()
+			gc.ArcTo(x, y, radius, radius, 0, 2*math.Pi)
+			gc.Close()
+			gc.FillStroke()
+		case count > 0:
+			strokes.stroke(prevX, prevY, x, y, 0.5*float64(scale), color.RGBA{255, 255, 255, 128})
+		}
+		prevX, prevY = x, y
+		count++
+	}
+	log.Printf("Streamed %d links\n", count)
+
+	if scale > 1 {
+		img = boxDownsample(img, scale)
+	}
+	return compositeLayers([]*image.RGBA{img}, hits, cfg, b), nil
+}
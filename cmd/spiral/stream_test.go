@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that streaming a spiral through a channel renders the same picture
+// as the batch renderer given the same bounds.
+func TestRenderLinksStream_MatchesBatch(t *testing.T) {
+	links := testutil.SpiralLinks(2000)
+	minX, maxX, minY, maxY := computeBounds(links)
+	cfg := RenderConfig{OutputSize: 128, Bounds: &Bounds{minX, maxX, minY, maxY}}
+
+	ch := make(chan complex128, 64)
+	go func() {
+		defer close(ch)
+		for _, link := range links {
+			ch <- link
+		}
+	}()
+	streamed, err := RenderLinksStream(ch, cfg)
+	if err != nil {
+		t.Fatalf("RenderLinksStream: %v", err)
+	}
+	batch := renderLinks(links, cfg)
+
+	// The batch render strokes each worker's share separately and adds the
+	// layers, so only pixels where chunks meet or overlap may differ.
+	lit, differ := 0, 0
+	for i := 0; i < len(batch.Pix); i += 4 {
+		a, b := int(batch.Pix[i]), int(streamed.Pix[i])
+		if a > 40 || b > 40 {
+			lit++
+			if a-b > 64 || b-a > 64 {
+				differ++
+			}
+		}
+	}
+	t.Logf("%d of %d lit pixels differ", differ, lit)
+	if lit == 0 {
+		t.Fatal("nothing was drawn")
+	}
+	if differ > lit/20 {
+		t.Errorf("%d of %d lit pixels differ between the streamed and batch renders", differ, lit)
+	}
+}
+
+// Test that a stream without bounds is rejected before anything is read.
+func TestRenderLinksStream_RequiresBounds(t *testing.T) {
+	ch := make(chan complex128, 1)
+	ch <- 1
+	if _, err := RenderLinksStream(ch, RenderConfig{OutputSize: 64}); err == nil {
+		t.Fatal("expected an error without bounds")
+	}
+	if len(ch) != 1 {
+		t.Error("link was consumed despite the error")
+	}
+}
+
+// Test that options the stream can't honor are rejected before anything is
+// read, rather than silently ignored.
+func TestRenderLinksStream_RejectsUnsupported(t *testing.T) {
+	bounds := &Bounds{-1, 1, -1, 1}
+	testCases := []struct {
+		name string
+		cfg  RenderConfig
+	}{
+		{"fade", RenderConfig{OutputSize: 64, Bounds: bounds, Fade: 100}},
+		{"mirror", RenderConfig{OutputSize: 64, Bounds: bounds, Mirror: true}},
+		{"density alpha", RenderConfig{OutputSize: 64, Bounds: bounds, DensityAlpha: true}},
+		{"color by residual", RenderConfig{OutputSize: 64, Bounds: bounds, ColorByResidual: true}},
+		{"quiver", RenderConfig{OutputSize: 64, Bounds: bounds, Quiver: 10}},
+		{"simple renderer", RenderConfig{OutputSize: 64, Bounds: bounds, Renderer: RendererSimple}},
+	}
+
+	for _, tc := range testCases {
+		ch := make(chan complex128, 1)
+		ch <- 1
+		if _, err := RenderLinksStream(ch, tc.cfg); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+		if len(ch) != 1 {
+			t.Errorf("%s: link was consumed despite the error", tc.name)
+		}
+	}
+}
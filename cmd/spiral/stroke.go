@@ -0,0 +1,121 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/golang/freetype/raster"
+	"github.com/llgcode/draw2d/draw2dimg"
+	"golang.org/x/image/math/fixed"
+)
+
+// layerPainter paints the spans draw2d rasterizes onto a worker's layer
+// with the blend mode, so it applies between every two strokes and not
+// just between layers, and, when hits is set, counts their coverage into
+// it.
+type layerPainter struct {
+	*raster.RGBAPainter
+	additive bool
+	hits     *hitBuffer
+	// rgba is the premultiplied 16-bit paint color.
+	rgba [4]uint32
+}
+
+// newLayerPainter returns a layerPainter for img that blends as blend
+// selects, counting into hits when it is non-nil.
+func newLayerPainter(img *image.RGBA, blend string, hits *hitBuffer) *layerPainter {
+	return &layerPainter{RGBAPainter: raster.NewRGBAPainter(img), additive: blend != BlendOver, hits: hits}
+}
+
+// SetColor satisfies draw2dimg.Painter.
+func (p *layerPainter) SetColor(c color.Color) {
+	p.RGBAPainter.SetColor(c)
+	r, g, b, a := c.RGBA()
+	p.rgba = [4]uint32{r, g, b, a}
+}
+
+// Paint satisfies raster.Painter.
+func (p *layerPainter) Paint(ss []raster.Span, done bool) {
+	if p.hits != nil {
+		p.hits.addSpans(ss, p.rgba[3])
+	}
+	if !p.additive {
+		p.RGBAPainter.Paint(ss, done)
+		return
+	}
+	// Add the color, scaled by each span's coverage, clamping at white.
+	img := p.Image
+	b := img.Bounds()
+	for _, s := range ss {
+		if s.Y < b.Min.Y || s.Y >= b.Max.Y {
+			continue
+		}
+		x0, x1 := max(s.X0, b.Min.X), min(s.X1, b.Max.X)
+		var add [4]uint32
+		for c := range add {
+			add[c] = p.rgba[c] * s.Alpha / 0xffff >> 8
+		}
+		for x := x0; x < x1; x++ {
+			offset := img.PixOffset(x, s.Y)
+			for c := range add {
+				img.Pix[offset+c] = uint8(min(uint32(img.Pix[offset+c])+add[c], 255))
+			}
+		}
+	}
+}
+
+// segmentStroker strokes single segments through a painter, the way a
+// draw2d GraphicContext strokes a one-segment path: a quad the line width
+// across, with butt ends. A GraphicContext scans every row of its image on
+// each stroke, which dominates when each of millions of segments is
+// stroked on its own, so segmentStroker rasterizes only the rows a segment
+// covers.
+type segmentStroker struct {
+	r       *raster.Rasterizer
+	painter draw2dimg.Painter
+	size    int
+}
+
+// newSegmentStroker returns a segmentStroker for a size x size layer.
+func newSegmentStroker(painter draw2dimg.Painter, size int) *segmentStroker {
+	return &segmentStroker{r: raster.NewRasterizer(size, size), painter: painter, size: size}
+}
+
+// stroke paints the segment from (x0, y0) to (x1, y1), width wide, in c.
+// Zero-length segments draw nothing, as with draw2d.
+func (s *segmentStroker) stroke(x0, y0, x1, y1, width float64, c color.Color) {
+	dx, dy := x1-x0, y1-y0
+	d := math.Hypot(dx, dy)
+	if !(d > 0) {
+		return
+	}
+	nx, ny := dy*width/2/d, -dx*width/2/d
+	corners := [4][2]float64{{x0 + nx, y0 + ny}, {x1 + nx, y1 + ny}, {x1 - nx, y1 - ny}, {x0 - nx, y0 - ny}}
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, p := range corners {
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+	}
+	top := int(math.Max(math.Floor(minY), 0))
+	bottom := int(math.Min(math.Ceil(maxY), float64(s.size)))
+	if top >= bottom {
+		return
+	}
+
+	// Rasterize the rows from top to bottom only, shifting the spans back
+	// into place.
+	s.r.SetBounds(s.size, bottom-top)
+	s.r.Dy = top
+	s.r.UseNonZeroWinding = true
+	point := func(p [2]float64) fixed.Point26_6 {
+		return fixed.Point26_6{X: fixed.Int26_6(p[0] * 64), Y: fixed.Int26_6((p[1] - float64(top)) * 64)}
+	}
+	s.r.Start(point(corners[0]))
+	for _, p := range corners[1:] {
+		s.r.Add1(point(p))
+	}
+	s.r.Add1(point(corners[0]))
+	s.painter.SetColor(c)
+	s.r.Rasterize(s.painter)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parseRange parses a -range value "start:end" of link indices, half-open
+// like a Go slice expression, and must not be empty. Either side may be left
+// out: a missing start is 0, and a missing end, returned as -1, runs to the
+// end of the chain.
+func parseRange(value string) (start, end int, err error) {
+	lo, hi, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid -range %q: want start:end", value)
+	}
+	start, end = 0, -1
+	if lo = strings.TrimSpace(lo); lo != "" {
+		if start, err = strconv.Atoi(lo); err != nil {
+			return 0, 0, fmt.Errorf("invalid -range start %q: %v", lo, err)
+		}
+	}
+	if hi = strings.TrimSpace(hi); hi != "" {
+		if end, err = strconv.Atoi(hi); err != nil {
+			return 0, 0, fmt.Errorf("invalid -range end %q: %v", hi, err)
+		}
+	}
+	if start < 0 || (end >= 0 && end <= start) {
+		return 0, 0, fmt.Errorf("invalid -range %q: want 0 <= start < end", value)
+	}
+	return start, end, nil
+}
+
+// subChain returns a copy of links[start:end], with end -1 meaning
+// len(links). Copying lets the rest of a long chain be freed. It is an
+// error for the range to run past the chain or to select no links.
+func subChain[T linkValue](links []T, start, end int) ([]T, error) {
+	if end < 0 {
+		end = len(links)
+	}
+	if start >= end || end > len(links) {
+		return nil, fmt.Errorf("-range %d:%d is outside the chain of %d links", start, end, len(links))
+	}
+	return slices.Clone(links[start:end]), nil
+}
@@ -0,0 +1,26 @@
+package main
+
+// Term accumulation orders accepted by -sum-order.
+const (
+	// SumAscending adds terms from k = 1 up, the natural order for
+	// building the links.
+	SumAscending = "ascending"
+	// SumDescending adds terms from the largest k down, smallest magnitude
+	// first, so the small tail terms aren't rounded away against a large
+	// running sum.
+	SumDescending = "descending"
+)
+
+// validSumOrder reports whether name is a known accumulation order.
+func validSumOrder(name string) bool {
+	return name == SumAscending || name == SumDescending
+}
+
+// sumDescending adds values from last to first.
+func sumDescending(values []complex128) complex128 {
+	var sum complex128
+	for i := len(values) - 1; i >= 0; i-- {
+		sum += values[i]
+	}
+	return sum
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"zeta-scale-go/pkg/compression"
+)
+
+// threeJSBounds is the bounding box included with a three.js export so a
+// viewer can frame its camera without scanning the positions.
+type threeJSBounds struct {
+	MinX float64 `json:"minX"`
+	MaxX float64 `json:"maxX"`
+	MinY float64 `json:"minY"`
+	MaxY float64 `json:"maxY"`
+}
+
+// threeJSGeometry is the JSON document written by ExportThreeJSON.
+type threeJSGeometry struct {
+	// Positions holds x, y, z triples ready for a BufferGeometry
+	// position attribute; z is always 0.
+	Positions []float64     `json:"positions"`
+	Bounds    threeJSBounds `json:"bounds"`
+}
+
+// ExportThreeJSON writes the links as a JSON object with a flat positions
+// array of (real, imag, 0) triples, suitable for a three.js BufferGeometry
+// drawn with Line or LineSegments, plus the bounds for camera framing.
+func ExportThreeJSON(links []complex128, w io.Writer) error {
+	geometry := threeJSGeometry{Positions: make([]float64, 0, 3*len(links))}
+	for _, link := range links {
+		geometry.Positions = append(geometry.Positions, real(link), imag(link), 0)
+	}
+	if len(links) > 0 {
+		b := boundsOf(links)
+		geometry.Bounds = threeJSBounds{MinX: b.MinX, MaxX: b.MaxX, MinY: b.MinY, MaxY: b.MaxY}
+	}
+	return json.NewEncoder(w).Encode(geometry)
+}
+
+// saveLinksThreeJSON exports links to a three.js JSON file, atomically.
+func saveLinksThreeJSON(links []complex128, filename string) error {
+	return compression.WriteFileAtomic(filename, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		if err := ExportThreeJSON(links, bw); err != nil {
+			return err
+		}
+		return bw.Flush()
+	})
+}
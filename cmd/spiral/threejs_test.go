@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// Test that the export holds one x, y, 0 triple per link and the bounds.
+func TestExportThreeJSON(t *testing.T) {
+	links := []complex128{complex(1, 2), complex(-3, 0.5), complex(0, -4)}
+
+	var buf bytes.Buffer
+	if err := ExportThreeJSON(links, &buf); err != nil {
+		t.Fatalf("ExportThreeJSON: %v", err)
+	}
+
+	var got threeJSGeometry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(got.Positions) != 3*len(links) {
+		t.Fatalf("got %d positions, want %d", len(got.Positions), 3*len(links))
+	}
+	for i, link := range links {
+		x, y, z := got.Positions[3*i], got.Positions[3*i+1], got.Positions[3*i+2]
+		if x != real(link) || y != imag(link) || z != 0 {
+			t.Errorf("triple %d: got (%v, %v, %v), want (%v, %v, 0)", i, x, y, z, real(link), imag(link))
+		}
+	}
+
+	want := threeJSBounds{MinX: -3, MaxX: 1, MinY: -4, MaxY: 2}
+	if got.Bounds != want {
+		t.Errorf("got bounds %+v, want %+v", got.Bounds, want)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"time"
+)
+
+// ParallelDownsampleThreshold is the link count at which downsampleComplex
+// switches from the serial to the parallel path. Below it, goroutine startup
+// and the chunk merge cost more than the work they split.
+var ParallelDownsampleThreshold = 10000
+
+// calibrationSizes are the link counts tried by calibrateParallelThreshold.
+var calibrationSizes = []int{1250, 2500, 5000, 10000, 20000, 40000, 80000}
+
+// calibrationRuns is how many timings are taken per path and size; the
+// fastest is kept to filter out scheduler noise.
+const calibrationRuns = 3
+
+// calibrateParallelThreshold times both downsampling paths on a synthetic
+// spiral at increasing sizes and returns the smallest size at which the
+// parallel path wins. If it never wins, the threshold is placed beyond the
+// largest size tried.
+func calibrateParallelThreshold(outputSize int, aggressiveness float64) int {
+	aggressiveness = clampAggressiveness(aggressiveness)
+	largest := calibrationSizes[len(calibrationSizes)-1]
+	links := calibrationSpiral(largest)
+
+	for _, n := range calibrationSizes {
+		sample := links[:n]
+		serial := fastestRun(func() { downsampleComplexSerial(sample, outputSize, aggressiveness, DownsampleOptions{}, false) })
+		parallel := fastestRun(func() { downsampleComplexParallel(sample, outputSize, aggressiveness, DownsampleOptions{}, false) })
+		if parallel < serial {
+			return n
+		}
+	}
+	return 2 * largest
+}
+
+// calibrationSpiral returns n points on an expanding spiral, dense enough
+// that neighbouring points share pixels as zeta partial sums do.
+func calibrationSpiral(n int) []complex128 {
+	links := make([]complex128, n)
+	for i := range links {
+		t := float64(i) / float64(n)
+		links[i] = cmplx.Rect(10*t, 40*math.Pi*t)
+	}
+	return links
+}
+
+// fastestRun returns the shortest of calibrationRuns timings of f.
+func fastestRun(f func()) time.Duration {
+	best := time.Duration(math.MaxInt64)
+	for i := 0; i < calibrationRuns; i++ {
+		start := time.Now()
+		f()
+		best = min(best, time.Since(start))
+	}
+	return best
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseWindow parses a -window value: "minX,maxX,minY,maxY" in data
+// coordinates, with each minimum below its maximum.
+func parseWindow(value string) (Bounds, error) {
+	fields := strings.Split(value, ",")
+	if len(fields) != 4 {
+		return Bounds{}, fmt.Errorf("invalid -window %q: want minX,maxX,minY,maxY", value)
+	}
+	var v [4]float64
+	for i, field := range fields {
+		f, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return Bounds{}, fmt.Errorf("invalid -window value %q: %v", field, err)
+		}
+		v[i] = f
+	}
+	b := Bounds{MinX: v[0], MaxX: v[1], MinY: v[2], MaxY: v[3]}
+	if !(b.MinX < b.MaxX && b.MinY < b.MaxY) {
+		return Bounds{}, fmt.Errorf("invalid -window %q: each minimum must be below its maximum", value)
+	}
+	return b, nil
+}
+
+// inFrame reports whether the pixel-space point (x, y) lies in the
+// size x size frame.
+func inFrame(x, y, size float64) bool {
+	return x >= 0 && x <= size && y >= 0 && y <= size
+}
+
+// clipSegment clips the pixel-space segment from (x0, y0) to (x1, y1) to the
+// size x size frame with the Liang-Barsky algorithm. ok is false if no part
+// of the segment lies in the frame; otherwise the returned end points are
+// the originals or, where the segment crosses the edge, the crossing.
+func clipSegment(x0, y0, x1, y1, size float64) (cx0, cy0, cx1, cy1 float64, ok bool) {
+	dx, dy := x1-x0, y1-y0
+	t0, t1 := 0.0, 1.0
+	// Each edge as p*t <= q: left, right, top, bottom.
+	for _, edge := range [4][2]float64{{-dx, x0}, {dx, size - x0}, {-dy, y0}, {dy, size - y0}} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			// Parallel to this edge: wholly outside or irrelevant.
+			if q < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 {
+			if t > t1 {
+				return 0, 0, 0, 0, false
+			}
+			t0 = max(t0, t)
+		} else {
+			if t < t0 {
+				return 0, 0, 0, 0, false
+			}
+			t1 = min(t1, t)
+		}
+	}
+	// Keep untouched ends exact so consecutive visible segments join.
+	cx0, cy0, cx1, cy1 = x0, y0, x1, y1
+	if t0 > 0 {
+		cx0, cy0 = x0+t0*dx, y0+t0*dy
+	}
+	if t1 < 1 {
+		cx1, cy1 = x0+t1*dx, y0+t1*dy
+	}
+	return cx0, cy0, cx1, cy1, true
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"runtime"
+	"sync"
+)
+
+var (
+	// ErrZetaPole is returned for s = 1, where ζ has its only pole.
+	ErrZetaPole = errors.New("zeta: pole at s = 1")
+	// ErrZetaDomain is returned for Re(s) <= -1, where the Euler-Maclaurin
+	// correction kept here (through the ½N^{-s} term) no longer converges.
+	ErrZetaDomain = errors.New("zeta: Re(s) <= -1 is outside the supported domain")
+)
+
+// Zeta evaluates ζ(s) with the Euler-Maclaurin partial sums used for the spiral.
+// It does not validate s; see ZetaChecked.
+func Zeta(s complex128) complex128 {
+	return calculateSpiralSum(s, ChunkConfig{})
+}
+
+// ZetaChecked is Zeta with the domain made explicit. Real inputs need no
+// special path: for s > 1 the sum converges to the real ζ(s), and for
+// -1 < Re(s) < 1 (including the real point s = 1/2) the Euler-Maclaurin
+// correction terms supply the analytic continuation rather than the
+// divergent raw partial sum. It returns ErrZetaPole at s = 1 and
+// ErrZetaDomain for Re(s) <= -1.
+func ZetaChecked(s complex128) (complex128, error) {
+	if s == 1 {
+		return 0, ErrZetaPole
+	}
+	if real(s) <= -1 {
+		return 0, ErrZetaDomain
+	}
+	return Zeta(s), nil
+}
+
+// ZetaPrimeNumeric estimates ζ'(s) numerically, to cross-check the analytic
+// -Σ log(k) k^{-s} series. For real s it takes the complex step
+// Im(ζ(s+ih))/h, which, having no subtraction, stays accurate for h as small
+// as 1e-20. That relies on ζ being real on the real axis, so for other s it
+// falls back to the central difference (ζ(s+h) - ζ(s-h))/2h along the real
+// direction, where h around 1e-5 balances truncation against cancellation.
+func ZetaPrimeNumeric(s complex128, h float64) complex128 {
+	if imag(s) == 0 {
+		return complex(imag(Zeta(s+complex(0, h)))/h, 0)
+	}
+	step := complex(h, 0)
+	return (Zeta(s+step) - Zeta(s-step)) / (2 * step)
+}
+
+// maxAdaptiveIterations caps how many times ZetaAdaptive doubles N, so an
+// unreachable eps still terminates (at 2^maxAdaptiveIterations times the
+// starting term count).
+const maxAdaptiveIterations = 16
+
+// ZetaAdaptive evaluates ζ(s) by Euler-Maclaurin, doubling the number of
+// terms from termCount(s, 0) until two successive estimates differ by less than
+// eps, or until maxAdaptiveIterations doublings or MaxN terms. It returns the
+// last estimate, the number of doublings made and the final difference,
+// which is the residual to compare against eps.
+func ZetaAdaptive(s complex128, eps float64) (value complex128, iterations int, residual float64) {
+	N := termCount(s, 0)
+	value = eulerMaclaurinSum(s, N)
+	residual = math.Inf(1)
+	for iterations < maxAdaptiveIterations && 2*N <= MaxN {
+		N *= 2
+		next := eulerMaclaurinSum(s, N)
+		residual = cmplx.Abs(next - value)
+		value = next
+		iterations++
+		if residual < eps {
+			break
+		}
+	}
+	return value, iterations, residual
+}
+
+// ZetaRichardson evaluates ζ(s) from the Euler-Maclaurin sums at n and n/2
+// terms, cancelling their leading truncation error (s/12)·N^{-s-1}. That
+// error grows by (n/(n/2))^{s+1}, or 2^{s+1} for even n, from the sum at n
+// to the one at n/2, which fixes the combination that removes it. n must be
+// at least 2.
+func ZetaRichardson(s complex128, n int) complex128 {
+	full := seriesSum(n, zetaTerm(s), zetaTail(s), ChunkConfig{})
+	half := seriesSum(n/2, zetaTerm(s), zetaTail(s), ChunkConfig{})
+	return richardsonExtrapolate(s, n, full, half)
+}
+
+// richardsonExtrapolate combines the ζ sums full at n and half at n/2 terms
+// as described for ZetaRichardson.
+func richardsonExtrapolate(s complex128, n int, full, half complex128) complex128 {
+	r := cmplx.Pow(complex(float64(n)/float64(n/2), 0), s+1)
+	return (r*full - half) / (r - 1)
+}
+
+// eulerMaclaurinSum returns Σ_{k<N} k^{-s} plus the ζ tail at N, summed
+// serially without keeping links.
+func eulerMaclaurinSum(s complex128, N int) complex128 {
+	var sum complex128
+	term := zetaTerm(s)
+	for k := 1; k < N; k++ {
+		sum += term(k)
+	}
+	return sum + zetaTail(s)(N)
+}
+
+// ZetaBatch evaluates Zeta for each input using a pool of workers, so a batch
+// of s values is parallelized across inputs rather than within one. Results
+// are returned in input order. A non-positive workers uses one per CPU.
+func ZetaBatch(inputs []complex128, workers int) []complex128 {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	results := make([]complex128, len(inputs))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i] = Zeta(inputs[i])
+			}
+		}()
+	}
+
+	for i := range inputs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
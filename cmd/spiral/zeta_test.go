@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Test that batch evaluation preserves input order and matches single calls.
+func TestZetaBatch(t *testing.T) {
+	inputs := []complex128{
+		complex(2, 0),
+		complex(0.5, 14.134725),
+		complex(3, 0),
+		complex(0.5, 21.022040),
+		complex(4, 1),
+	}
+
+	got := ZetaBatch(inputs, 3)
+	if len(got) != len(inputs) {
+		t.Fatalf("got %d results, want %d", len(got), len(inputs))
+	}
+	for i, s := range inputs {
+		if want := Zeta(s); got[i] != want {
+			t.Errorf("ZetaBatch[%d] for s=%v: got %v, want %v", i, s, got[i], want)
+		}
+	}
+
+	if !cmplxEquals(got[0], complex(math.Pi*math.Pi/6, 0), 1e-6) {
+		t.Errorf("ζ(2): got %v, want %v", got[0], math.Pi*math.Pi/6)
+	}
+}
+
+// Test evaluation on the real axis, where imag(s) = 0.
+func TestZetaChecked_RealAxis(t *testing.T) {
+	got, err := ZetaChecked(complex(2, 0))
+	if err != nil {
+		t.Fatalf("ζ(2): unexpected error %v", err)
+	}
+	if !cmplxEquals(got, complex(math.Pi*math.Pi/6, 0), 1e-6) {
+		t.Errorf("ζ(2): got %v, want %v", got, math.Pi*math.Pi/6)
+	}
+
+	// At s = 1/2 the raw partial sums diverge; the correction terms give the
+	// analytic continuation ζ(1/2) = -1.4603545088...
+	got, err = ZetaChecked(complex(0.5, 0))
+	if err != nil {
+		t.Fatalf("ζ(1/2): unexpected error %v", err)
+	}
+	if imag(got) != 0 || !floatEquals(real(got), -1.4603545088095868, 1e-4) {
+		t.Errorf("ζ(1/2): got %v, want -1.46035...", got)
+	}
+
+	if _, err := ZetaChecked(1); !errors.Is(err, ErrZetaPole) {
+		t.Errorf("ζ(1): got error %v, want %v", err, ErrZetaPole)
+	}
+	if _, err := ZetaChecked(complex(-2, 0)); !errors.Is(err, ErrZetaDomain) {
+		t.Errorf("ζ(-2): got error %v, want %v", err, ErrZetaDomain)
+	}
+}
+
+// Test that repeated lookups hit the cache instead of recomputing, that
+// near-identical s values share an entry, and that the LRU evicts.
+func TestZetaCache(t *testing.T) {
+	calls := 0
+	cache := newZetaCache(2, zetaCacheQuantum, func(s complex128) complex128 {
+		calls++
+		return Zeta(s)
+	})
+
+	s := complex(0.5, 14.134725)
+	first := cache.Get(s)
+	second := cache.Get(s)
+	if calls != 1 {
+		t.Errorf("got %d evaluations for two identical requests, want 1", calls)
+	}
+	if first != second || !cmplxEquals(first, Zeta(s), 1e-12) {
+		t.Errorf("got %v then %v, want %v", first, second, Zeta(s))
+	}
+
+	cache.Get(s + complex(0, zetaCacheQuantum/10))
+	if calls != 1 {
+		t.Errorf("got %d evaluations after a near-identical request, want 1", calls)
+	}
+
+	// Filling past capacity evicts the least recently used entry.
+	cache.Get(complex(2, 0))
+	cache.Get(complex(3, 0))
+	cache.Get(s)
+	if calls != 4 {
+		t.Errorf("got %d evaluations, want 4 after s was evicted", calls)
+	}
+
+	if got := CachedZeta(complex(2, 0)); !cmplxEquals(got, complex(math.Pi*math.Pi/6, 0), 1e-6) {
+		t.Errorf("CachedZeta(2): got %v, want %v", got, math.Pi*math.Pi/6)
+	}
+}
+
+// Test that a tighter eps costs more doublings and buys a smaller residual.
+func TestZetaAdaptive(t *testing.T) {
+	s := complex(0.5, 14.134725)
+
+	_, looseIters, looseResidual := ZetaAdaptive(s, 1e-3)
+	tight, tightIters, tightResidual := ZetaAdaptive(s, 1e-7)
+
+	if tightIters <= looseIters {
+		t.Errorf("got %d iterations for eps 1e-7, want more than %d for eps 1e-3", tightIters, looseIters)
+	}
+	if looseResidual >= 1e-3 || tightResidual >= 1e-7 {
+		t.Errorf("residuals %e and %e did not reach their eps", looseResidual, tightResidual)
+	}
+	if tightResidual >= looseResidual {
+		t.Errorf("got tight residual %e, want below loose residual %e", tightResidual, looseResidual)
+	}
+	// s is within 1e-6 of the first zero.
+	if got := cmplx.Abs(tight); got > 1e-5 {
+		t.Errorf("got |ζ(s)| = %e near the first zero, want about 0", got)
+	}
+
+	// An unreachable eps stops at the iteration cap instead of looping.
+	if _, iters, residual := ZetaAdaptive(complex(2, 0), 0); iters > maxAdaptiveIterations || residual == 0 {
+		t.Errorf("eps 0: got %d iterations and residual %e, want at most %d iterations", iters, residual, maxAdaptiveIterations)
+	}
+}
+
+// Test that without the MinN floor the |s| heuristic gives far too few terms
+// at s = 0.5+2i, and that fixing the term count recovers ζ(s).
+func TestZeta_ExplicitTerms(t *testing.T) {
+	defer func(minN int) { MinN = minN }(MinN)
+	s := complex(0.5, 2)
+	want := complex(0.44054565035, -0.31164633843)
+
+	MinN = 1
+	if N := termCount(s, 0); N != 2 {
+		t.Fatalf("heuristic N: got %d, want 2", N)
+	}
+	if err := cmplx.Abs(Zeta(s) - want); err < 1e-4 {
+		t.Errorf("heuristic N=2 error %g is unexpectedly small", err)
+	}
+
+	const terms = 100_000
+	if N := termCount(s, terms); N != terms {
+		t.Fatalf("got N %d with terms fixed, want %d", N, terms)
+	}
+	if got := calculateSpiralSum(s, ChunkConfig{Terms: terms}); !cmplxEquals(got, want, 1e-8) {
+		t.Errorf("ζ(%v) with %d terms: got %v, want %v", s, terms, got, want)
+	}
+}
+
+// Test that Richardson extrapolation from N and N/2 terms beats the plain
+// sum at N against a reference value of ζ(0.5+25i).
+func TestZetaRichardson(t *testing.T) {
+	s := complex(0.5, 25)
+	want := complex(0.00498459336, -0.01401230197)
+
+	for _, n := range []int{100, 101, 1000} {
+		plainErr := cmplx.Abs(eulerMaclaurinSum(s, n) - want)
+		richErr := cmplx.Abs(ZetaRichardson(s, n) - want)
+		t.Logf("n=%d: plain error %g, Richardson error %g", n, plainErr, richErr)
+		if richErr >= plainErr/100 {
+			t.Errorf("n=%d: Richardson error %g is not well below the plain error %g", n, richErr, plainErr)
+		}
+	}
+
+	if got, want := ZetaByMethod(s, MethodRichardson), ZetaRichardson(s, termCount(s, 0)); got != want {
+		t.Errorf("ZetaByMethod(%v, %q): got %v, want %v", s, MethodRichardson, got, want)
+	}
+}
+
+// Test that the complex-step derivative agrees with the analytic ζ' series
+// at s = 2, and both with ζ'(2) ≈ -0.9375482543.
+func TestZetaPrimeNumeric(t *testing.T) {
+	s := complex(2, 0)
+	numeric := ZetaPrimeNumeric(s, 1e-20)
+	analytic := calculateSeriesSum(s, zetaDerivativeTerm(s), zetaDerivativeTail(s), ChunkConfig{})
+	if !cmplxEquals(numeric, analytic, 1e-10) {
+		t.Errorf("got numeric ζ'(2) = %v, want the analytic %v", numeric, analytic)
+	}
+	if !cmplxEquals(numeric, complex(-0.9375482543158437, 0), 1e-6) {
+		t.Errorf("got ζ'(2) = %v, want -0.9375482543", numeric)
+	}
+
+	// Off the real axis the central difference stands in for the complex step.
+	s = complex(0.5, 20)
+	numeric = ZetaPrimeNumeric(s, 1e-5)
+	analytic = calculateSeriesSum(s, zetaDerivativeTerm(s), zetaDerivativeTail(s), ChunkConfig{})
+	if !cmplxEquals(numeric, analytic, 1e-6) {
+		t.Errorf("got numeric ζ'(%v) = %v, want the analytic %v", s, numeric, analytic)
+	}
+}
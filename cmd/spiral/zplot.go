@@ -0,0 +1,65 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/llgcode/draw2d/draw2dimg"
+)
+
+// zplotMargin is the fraction of the image height left above and below the
+// tallest of the curve and its envelope.
+const zplotMargin = 0.1
+
+// renderZCurve plots Z(t) from ZCurve as a white line over its ±ZEnvelope
+// band in dark red, with the t axis in gray, on a size x size image. t runs
+// left to right across the full width, and the Z scale is symmetric about
+// the axis.
+func renderZCurve(ts, zs []float64, size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	gc := draw2dimg.NewGraphicContext(img)
+	gc.SetFillColor(color.RGBA{0, 0, 0, 255})
+	gc.Clear()
+	if len(ts) < 2 {
+		return img
+	}
+
+	var zMax float64
+	for i, z := range zs {
+		zMax = max(zMax, math.Abs(z), ZEnvelope(ts[i]))
+	}
+	if zMax == 0 {
+		zMax = 1
+	}
+	tMin, tMax := ts[0], ts[len(ts)-1]
+	px := func(t float64) float64 { return (t - tMin) / (tMax - tMin) * float64(size) }
+	py := func(z float64) float64 {
+		return float64(size) / 2 * (1 - z/zMax*(1-zplotMargin))
+	}
+
+	trace := func(value func(i int) float64) {
+		gc.BeginPath()
+		gc.MoveTo(px(ts[0]), py(value(0)))
+		for i := 1; i < len(ts); i++ {
+			gc.LineTo(px(ts[i]), py(value(i)))
+		}
+		gc.Stroke()
+	}
+
+	gc.SetLineWidth(1)
+	gc.SetStrokeColor(color.RGBA{128, 128, 128, 255})
+	gc.BeginPath()
+	gc.MoveTo(0, py(0))
+	gc.LineTo(float64(size), py(0))
+	gc.Stroke()
+
+	gc.SetStrokeColor(color.RGBA{160, 30, 30, 255})
+	trace(func(i int) float64 { return ZEnvelope(ts[i]) })
+	trace(func(i int) float64 { return -ZEnvelope(ts[i]) })
+
+	gc.SetLineWidth(1.5)
+	gc.SetStrokeColor(color.RGBA{255, 255, 255, 255})
+	trace(func(i int) float64 { return zs[i] })
+	return img
+}
@@ -4,12 +4,19 @@ go 1.23.4
 
 require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
+	github.com/klauspost/pgzip v1.2.6
 	github.com/llgcode/draw2d v0.0.0-20240627062922-0ed1ff131195
+	github.com/parquet-go/parquet-go v0.25.1
 	github.com/vmihailenco/msgpack/v5 v5.4.1
+	golang.org/x/image v0.18.0
 	google.golang.org/protobuf v1.36.5
 )
 
 require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.21 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
-	golang.org/x/image v0.18.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
 )
@@ -0,0 +1,29 @@
+// Package testutil provides fixtures shared by the tests and benchmarks of
+// the spiral tools, so codecs, renderers and the downsampler are measured on
+// the same data.
+package testutil
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// SpiralTurns and SpiralRadius describe the shape SpiralLinks generates.
+const (
+	SpiralTurns  = 20
+	SpiralRadius = 10
+)
+
+// SpiralLinks returns n points on an Archimedean spiral that winds
+// SpiralTurns times around the origin, its radius growing linearly from 0
+// towards SpiralRadius.
+func SpiralLinks(n int) []complex128 {
+	links := make([]complex128, n)
+	for i := 0; i < n; i++ {
+		t := float64(i) / float64(n)
+		r := t * SpiralRadius
+		theta := t * SpiralTurns * 2 * math.Pi
+		links[i] = cmplx.Rect(r, theta)
+	}
+	return links
+}
@@ -0,0 +1,38 @@
+package testutil
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Test that SpiralLinks returns n points starting at the origin, with a
+// radius growing linearly towards SpiralRadius over SpiralTurns turns.
+func TestSpiralLinks_Shape(t *testing.T) {
+	n := 1000
+	links := SpiralLinks(n)
+	if len(links) != n {
+		t.Fatalf("got %d links, want %d", len(links), n)
+	}
+	if links[0] != 0 {
+		t.Errorf("got first link %v, want the origin", links[0])
+	}
+
+	for i, link := range links {
+		want := float64(i) / float64(n) * SpiralRadius
+		if got := cmplx.Abs(link); math.Abs(got-want) > 1e-9 {
+			t.Fatalf("link %d: got radius %v, want %v", i, got, want)
+		}
+	}
+
+	// Each full turn crosses the positive real axis from below once.
+	turns := 0
+	for i := 1; i < n; i++ {
+		if real(links[i]) > 0 && imag(links[i-1]) < 0 && imag(links[i]) >= 0 {
+			turns++
+		}
+	}
+	if turns != SpiralTurns-1 {
+		t.Errorf("got %d axis crossings, want %d", turns, SpiralTurns-1)
+	}
+}
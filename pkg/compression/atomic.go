@@ -0,0 +1,58 @@
+package compression
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes to a temporary file in the same directory as filename
+// and renames it into place only once write, a sync to disk and the close
+// all succeed. A crash or error mid-write therefore never leaves a partial
+// file behind, and any existing file at filename is untouched until the new
+// one is complete. The sync keeps a crash just after the rename from leaving
+// filename pointing at data that never reached the disk.
+func WriteFileAtomic(filename string, write func(w io.Writer) error) (err error) {
+	dir, base := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		log.Printf("Error creating temp file: %v", err)
+		return err
+	}
+	tmpName := tmp.Name()
+
+	// Clean up the temp file on any failure path.
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	// CreateTemp uses 0600; match the permissions os.Create would have given.
+	if err = tmp.Chmod(0o644); err != nil {
+		log.Printf("Error setting temp file permissions: %v", err)
+		return err
+	}
+	if err = write(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		log.Printf("Error syncing temp file: %v", err)
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		log.Printf("Error closing temp file: %v", err)
+		return err
+	}
+	if err = os.Rename(tmpName, filename); err != nil {
+		log.Printf("Error renaming temp file into place: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package compression
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test that a failed write leaves the existing target untouched and removes
+// the temp file.
+func TestWriteFileAtomic_WriteError(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "spiral.delta")
+	if err := os.WriteFile(target, []byte("original"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	errBoom := errors.New("boom")
+	err := WriteFileAtomic(target, func(w io.Writer) error {
+		if _, err := w.Write([]byte("partial")); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("got error %v, want %v", err, errBoom)
+	}
+
+	got, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "original" {
+		t.Errorf("target was modified: got %q, want %q", got, "original")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("expected only the target to remain, found %v", names)
+	}
+}
+
+// Test that a successful write replaces the target.
+func TestWriteFileAtomic_Success(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "spiral.msgpack")
+	if err := os.WriteFile(target, []byte("original"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteFileAtomic(target, func(w io.Writer) error {
+		_, err := w.Write([]byte("replacement"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "replacement" {
+		t.Errorf("got %q, want %q", got, "replacement")
+	}
+}
@@ -0,0 +1,141 @@
+package compression
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/cmplx"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// pointBounds returns the min/max of the points the way a renderer would
+// before setting up its viewport.
+func pointBounds(points []complex128) (minX, maxX, minY, maxY float64) {
+	minX, maxX = real(points[0]), real(points[0])
+	minY, maxY = imag(points[0]), imag(points[0])
+	for _, p := range points {
+		minX = math.Min(minX, real(p))
+		maxX = math.Max(maxX, real(p))
+		minY = math.Min(minY, imag(p))
+		maxY = math.Max(maxY, imag(p))
+	}
+	return minX, maxX, minY, maxY
+}
+
+// BenchmarkDecodeForRender measures load + decode + bounds, i.e. the latency
+// before a frontend can draw its first frame, for each saved format.
+func BenchmarkDecodeForRender(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, size := range []int{100_000, 1_000_000} {
+		dir := b.TempDir()
+		points := testutil.SpiralLinks(size)
+
+		deltaFile := filepath.Join(dir, "spiral.delta")
+		delta, err := CompressWithDelta(points)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := SaveDeltaCompressed(delta, deltaFile); err != nil {
+			b.Fatal(err)
+		}
+
+		msgpackFile := filepath.Join(dir, "spiral.msgpack")
+		packed, err := CompressWithMsgPack(points)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := SaveMsgPack(packed, msgpackFile); err != nil {
+			b.Fatal(err)
+		}
+
+		// Correctness guard: both formats must describe the same spiral.
+		loadedDelta, err := LoadDeltaCompressed(deltaFile)
+		if err != nil {
+			b.Fatal(err)
+		}
+		loadedPacked, err := LoadMsgPack(msgpackFile)
+		if err != nil {
+			b.Fatal(err)
+		}
+		fromDelta, err := loadedDelta.Decompress()
+		if err != nil {
+			b.Fatal(err)
+		}
+		fromPacked := loadedPacked.Decompress()
+		if len(fromDelta) != len(fromPacked) {
+			b.Fatalf("decoded lengths differ: delta %d, msgpack %d", len(fromDelta), len(fromPacked))
+		}
+		minX, maxX, minY, maxY := pointBounds(points)
+		tolerance := 0.01 * math.Max(maxX-minX, maxY-minY)
+		for i := range fromDelta {
+			if d := cmplx.Abs(fromDelta[i] - fromPacked[i]); d > tolerance {
+				b.Fatalf("point %d differs by %e between formats (tolerance %e)", i, d, tolerance)
+			}
+		}
+
+		b.Run(fmt.Sprintf("Delta/Size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				loaded, err := LoadDeltaCompressed(deltaFile)
+				if err != nil {
+					b.Fatal(err)
+				}
+				decoded, err := loaded.Decompress()
+				if err != nil {
+					b.Fatal(err)
+				}
+				pointBounds(decoded)
+			}
+			b.ReportMetric(float64(size)*float64(b.N)/b.Elapsed().Seconds(), "points/s")
+		})
+
+		b.Run(fmt.Sprintf("MsgPack/Size=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				loaded, err := LoadMsgPack(msgpackFile)
+				if err != nil {
+					b.Fatal(err)
+				}
+				pointBounds(loaded.Decompress())
+			}
+			b.ReportMetric(float64(size)*float64(b.N)/b.Elapsed().Seconds(), "points/s")
+		})
+	}
+}
+
+// BenchmarkSaveDeltaGzip compares serial and parallel gzip for large saves.
+func BenchmarkSaveDeltaGzip(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	defer func(orig bool) { ParallelGzip = orig }(ParallelGzip)
+
+	for _, size := range []int{1_000_000, 5_000_000} {
+		delta, err := CompressWithDelta(testutil.SpiralLinks(size))
+		if err != nil {
+			b.Fatal(err)
+		}
+		filename := filepath.Join(b.TempDir(), "spiral.delta")
+		rawBytes := float64(len(delta.Deltas) * 2)
+
+		for _, parallel := range []bool{false, true} {
+			name := "Serial"
+			if parallel {
+				name = "Parallel"
+			}
+			b.Run(fmt.Sprintf("%s/Size=%d", name, size), func(b *testing.B) {
+				ParallelGzip = parallel
+				for i := 0; i < b.N; i++ {
+					if err := SaveDeltaCompressed(delta, filename); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(rawBytes*float64(b.N)/b.Elapsed().Seconds()/(1024*1024), "MB/s")
+			})
+		}
+	}
+}
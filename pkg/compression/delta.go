@@ -3,11 +3,19 @@ package compression
 import (
 	"compress/gzip"
 	"encoding/binary"
+	"fmt"
+	"io"
 	"log"
 	"math"
 	"os"
 )
 
+// KeyframeInterval, when positive, makes CompressWithDelta store every
+// KeyframeInterval-th point in full alongside its delta. Decompress restarts
+// from each keyframe, so the rounding error of the deltas, which otherwise
+// accumulates along the whole chain, only builds up between keyframes.
+var KeyframeInterval = 0
+
 // DeltaCompressed represents a spiral compressed using delta encoding
 type DeltaCompressed struct {
 	// Store first point in full precision
@@ -18,21 +26,47 @@ type DeltaCompressed struct {
 	NumPoints uint32
 	// Packed deltas using int16 for efficiency
 	Deltas []int16
+	// Correction is added to the last point on decompression. Storing the
+	// final Euler-Maclaurin correction here rather than in the deltas keeps
+	// its one large jump from setting the scale for every other delta.
+	CorrectionX, CorrectionY float64
+	// KeyframeInterval is the spacing of the keyframes, or 0 for none.
+	KeyframeInterval uint32
+	// Keyframes holds the full-precision x, y pairs of points
+	// KeyframeInterval, 2*KeyframeInterval, ... before the correction is
+	// added. Their indices follow from the interval, so only it is stored.
+	Keyframes []float64
 }
 
 // CompressWithDelta compresses the points using delta encoding
 func CompressWithDelta(points []complex128) (*DeltaCompressed, error) {
+	return CompressWithDeltaCorrection(points, 0)
+}
+
+// CompressWithDeltaCorrection delta-encodes points whose last point has
+// correction added, storing the correction apart from the deltas: the
+// deltas encode the raw chain ending at the last point minus correction,
+// and Decompress adds it back.
+func CompressWithDeltaCorrection(points []complex128, correction complex128) (*DeltaCompressed, error) {
 	if len(points) == 0 {
 		return nil, nil
 	}
+	if correction != 0 {
+		raw := make([]complex128, len(points))
+		copy(raw, points)
+		raw[len(raw)-1] -= correction
+		points = raw
+	}
 
 	log.Printf("Starting delta compression of %d points", len(points))
 
 	// Initialize with first point
 	compressed := &DeltaCompressed{
-		StartX:    real(points[0]),
-		StartY:    imag(points[0]),
-		NumPoints: uint32(len(points)),
+		StartX:      real(points[0]),
+		StartY:      imag(points[0]),
+		NumPoints:   uint32(len(points)),
+		CorrectionX: real(correction),
+		CorrectionY: imag(correction),
 	}
 
 	// Calculate ranges to determine optimal scale factors
@@ -70,8 +104,15 @@ func CompressWithDelta(points []complex128) (*DeltaCompressed, error) {
 		dx := real(points[i]) - real(points[i-1])
 		dy := imag(points[i]) - imag(points[i-1])
 
-		compressed.Deltas[(i-1)*2] = int16(dx / compressed.ScaleX)
-		compressed.Deltas[(i-1)*2+1] = int16(dy / compressed.ScaleY)
+		compressed.Deltas[(i-1)*2] = int16(math.Round(dx / compressed.ScaleX))
+		compressed.Deltas[(i-1)*2+1] = int16(math.Round(dy / compressed.ScaleY))
+	}
+
+	if KeyframeInterval > 0 {
+		compressed.KeyframeInterval = uint32(KeyframeInterval)
+		for i := KeyframeInterval; i < len(points); i += KeyframeInterval {
+			compressed.Keyframes = append(compressed.Keyframes, real(points[i]), imag(points[i]))
+		}
 	}
 
 	log.Printf("Successfully compressed to %d deltas", len(compressed.Deltas))
@@ -82,46 +123,55 @@ func CompressWithDelta(points []complex128) (*DeltaCompressed, error) {
 func SaveDeltaCompressed(compressed *DeltaCompressed, filename string) error {
 	log.Printf("Starting to save delta compressed data to %s", filename)
 
-	file, err := os.Create(filename)
-	if err != nil {
-		log.Printf("Error creating file: %v", err)
-		return err
-	}
-	defer file.Close()
+	err := saveGzipped(filename, func(gzw io.Writer) error {
+		// Write header
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.StartX); err != nil {
+			log.Printf("Error writing StartX: %v", err)
+			return err
+		}
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.StartY); err != nil {
+			log.Printf("Error writing StartY: %v", err)
+			return err
+		}
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.ScaleX); err != nil {
+			log.Printf("Error writing ScaleX: %v", err)
+			return err
+		}
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.ScaleY); err != nil {
+			log.Printf("Error writing ScaleY: %v", err)
+			return err
+		}
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.NumPoints); err != nil {
+			log.Printf("Error writing NumPoints: %v", err)
+			return err
+		}
 
-	gzw := gzip.NewWriter(file)
-	defer gzw.Close()
+		// Write deltas
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.Deltas); err != nil {
+			log.Printf("Error writing Deltas: %v", err)
+			return err
+		}
 
-	// Write header
-	if err := binary.Write(gzw, binary.LittleEndian, compressed.StartX); err != nil {
-		log.Printf("Error writing StartX: %v", err)
-		return err
-	}
-	if err := binary.Write(gzw, binary.LittleEndian, compressed.StartY); err != nil {
-		log.Printf("Error writing StartY: %v", err)
-		return err
-	}
-	if err := binary.Write(gzw, binary.LittleEndian, compressed.ScaleX); err != nil {
-		log.Printf("Error writing ScaleX: %v", err)
-		return err
-	}
-	if err := binary.Write(gzw, binary.LittleEndian, compressed.ScaleY); err != nil {
-		log.Printf("Error writing ScaleY: %v", err)
-		return err
-	}
-	if err := binary.Write(gzw, binary.LittleEndian, compressed.NumPoints); err != nil {
-		log.Printf("Error writing NumPoints: %v", err)
-		return err
-	}
-
-	// Write deltas
-	if err := binary.Write(gzw, binary.LittleEndian, compressed.Deltas); err != nil {
-		log.Printf("Error writing Deltas: %v", err)
-		return err
-	}
+		// The correction trails the deltas, so readers that predate it
+		// still load the file, just without the correction.
+		correction := [2]float64{compressed.CorrectionX, compressed.CorrectionY}
+		if err := binary.Write(gzw, binary.LittleEndian, correction); err != nil {
+			log.Printf("Error writing Correction: %v", err)
+			return err
+		}
 
-	if err := gzw.Close(); err != nil {
-		log.Printf("Error closing gzip writer: %v", err)
+		// Keyframes trail the correction for the same reason.
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.KeyframeInterval); err != nil {
+			log.Printf("Error writing KeyframeInterval: %v", err)
+			return err
+		}
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.Keyframes); err != nil {
+			log.Printf("Error writing Keyframes: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -171,6 +221,12 @@ func LoadDeltaCompressed(filename string) (*DeltaCompressed, error) {
 		return nil, err
 	}
 
+	if compressed.NumPoints == 0 {
+		err := fmt.Errorf("delta header has no points")
+		log.Printf("Error reading NumPoints: %v", err)
+		return nil, err
+	}
+
 	// Read deltas
 	compressed.Deltas = make([]int16, (compressed.NumPoints-1)*2)
 	if err := binary.Read(gzr, binary.LittleEndian, &compressed.Deltas); err != nil {
@@ -178,16 +234,80 @@ func LoadDeltaCompressed(filename string) (*DeltaCompressed, error) {
 		return nil, err
 	}
 
+	if err := readDeltaTrailer(gzr, compressed); err != nil {
+		return nil, err
+	}
+
 	log.Printf("Successfully loaded %d points", compressed.NumPoints)
 	return compressed, nil
 }
 
-// Decompress converts the compressed data back to points
-func (c *DeltaCompressed) Decompress() []complex128 {
+// readDeltaTrailer reads the optional fields after the deltas into
+// compressed: the correction, then the keyframes. Files written before
+// either existed end early, which is not an error.
+func readDeltaTrailer(r io.Reader, compressed *DeltaCompressed) error {
+	var correction [2]float64
+	switch err := binary.Read(r, binary.LittleEndian, &correction); err {
+	case nil:
+		compressed.CorrectionX, compressed.CorrectionY = correction[0], correction[1]
+	case io.EOF:
+		return nil
+	default:
+		log.Printf("Error reading Correction: %v", err)
+		return err
+	}
+
+	switch err := binary.Read(r, binary.LittleEndian, &compressed.KeyframeInterval); err {
+	case nil:
+	case io.EOF:
+		return nil
+	default:
+		log.Printf("Error reading KeyframeInterval: %v", err)
+		return err
+	}
+	if compressed.KeyframeInterval > 0 {
+		compressed.Keyframes = make([]float64, 2*((compressed.NumPoints-1)/compressed.KeyframeInterval))
+		if err := binary.Read(r, binary.LittleEndian, &compressed.Keyframes); err != nil {
+			log.Printf("Error reading Keyframes: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Decompress converts the compressed data back to points, restarting from
+// each keyframe. It returns an error rather than panicking if NumPoints
+// disagrees with the number of deltas or keyframes, as happens with a
+// corrupt or truncated file.
+func (c *DeltaCompressed) Decompress() ([]complex128, error) {
+	if c.NumPoints == 0 {
+		if len(c.Deltas) != 0 {
+			return nil, fmt.Errorf("delta data has %d values but no points", len(c.Deltas))
+		}
+		return []complex128{}, nil
+	}
+	if want := (int(c.NumPoints) - 1) * 2; len(c.Deltas) != want {
+		return nil, fmt.Errorf("delta data has %d values, want %d for %d points",
+			len(c.Deltas), want, c.NumPoints)
+	}
+
+	interval := int(c.KeyframeInterval)
+	if interval > 0 {
+		if want := 2 * ((int(c.NumPoints) - 1) / interval); len(c.Keyframes) != want {
+			return nil, fmt.Errorf("delta data has %d keyframe values, want %d for %d points every %d",
+				len(c.Keyframes), want, c.NumPoints, interval)
+		}
+	}
+
 	points := make([]complex128, c.NumPoints)
 	points[0] = complex(c.StartX, c.StartY)
 
 	for i := 1; i < int(c.NumPoints); i++ {
+		if interval > 0 && i%interval == 0 {
+			k := 2 * (i/interval - 1)
+			points[i] = complex(c.Keyframes[k], c.Keyframes[k+1])
+			continue
+		}
 		dx := float64(c.Deltas[(i-1)*2]) * c.ScaleX
 		dy := float64(c.Deltas[(i-1)*2+1]) * c.ScaleY
 		points[i] = complex(
@@ -195,6 +315,7 @@ func (c *DeltaCompressed) Decompress() []complex128 {
 			imag(points[i-1])+dy,
 		)
 	}
+	points[len(points)-1] += complex(c.CorrectionX, c.CorrectionY)
 
-	return points
+	return points, nil
 }
@@ -0,0 +1,198 @@
+package compression
+
+import (
+	"math"
+	"math/cmplx"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that a header claiming more points than there are deltas is reported
+// as an error instead of panicking.
+func TestDecompress_MismatchedNumPoints(t *testing.T) {
+	compressed, err := CompressWithDelta([]complex128{0, complex(1, 1), complex(2, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := compressed.Decompress(); err != nil {
+		t.Fatalf("unexpected error for consistent data: %v", err)
+	}
+
+	for _, numPoints := range []uint32{0, 2, 4, 1000} {
+		corrupt := *compressed
+		corrupt.NumPoints = numPoints
+		points, err := corrupt.Decompress()
+		if err == nil {
+			t.Errorf("NumPoints=%d: expected an error, got %d points", numPoints, len(points))
+		}
+	}
+}
+
+// Test that storing the final correction apart from the deltas gives a much
+// smaller scale factor, and that the saved file still reconstructs the
+// corrected last point.
+func TestCompressWithDeltaCorrection(t *testing.T) {
+	// Small steps along a curve, then a corrected last point far away.
+	points := make([]complex128, 1000)
+	for i := range points {
+		points[i] = complex(float64(i)*0.001, math.Sin(float64(i)*0.01))
+	}
+	correction := complex(50, -30)
+	points[len(points)-1] += correction
+
+	inline, err := CompressWithDelta(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	separated, err := CompressWithDeltaCorrection(points, correction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if separated.ScaleX >= inline.ScaleX/100 || separated.ScaleY >= inline.ScaleY/100 {
+		t.Errorf("got scales (%g, %g) with the correction separated, want well below the inline (%g, %g)",
+			separated.ScaleX, separated.ScaleY, inline.ScaleX, inline.ScaleY)
+	}
+
+	filename := filepath.Join(t.TempDir(), "spiral.delta.gz")
+	if err := SaveDeltaCompressed(separated, filename); err != nil {
+		t.Fatalf("SaveDeltaCompressed: %v", err)
+	}
+	maxErr, err := VerifyDeltaFile(filename, points)
+	if err != nil {
+		t.Fatalf("VerifyDeltaFile: %v", err)
+	}
+	// Rounded deltas lose up to half a scale step each along the chain.
+	if bound := float64(len(points)) * math.Hypot(separated.ScaleX, separated.ScaleY) / 2; maxErr > bound {
+		t.Errorf("got reconstruction error %g, want at most %g", maxErr, bound)
+	}
+	if info, err := os.Stat(filename); err != nil || info.Size() > EstimateDeltaSize(len(points)) {
+		t.Errorf("delta file size over the estimate %d (stat error %v)", EstimateDeltaSize(len(points)), err)
+	}
+}
+
+// Test that the rounding error of the deltas accumulates along the chain
+// within N*scale/2 per axis, and that keyframes bound it by the keyframe
+// interval instead, surviving a save and load.
+func TestDelta_DriftAndKeyframes(t *testing.T) {
+	points := testutil.SpiralLinks(1 << 18)
+	axisErrors := func(decoded []complex128) (maxX, maxY float64) {
+		for i := range points {
+			maxX = math.Max(maxX, math.Abs(real(points[i]-decoded[i])))
+			maxY = math.Max(maxY, math.Abs(imag(points[i]-decoded[i])))
+		}
+		return maxX, maxY
+	}
+
+	plain, err := CompressWithDelta(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := plain.Decompress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	driftX, driftY := axisErrors(decoded)
+	n := float64(len(points) - 1)
+	if driftX > n*plain.ScaleX/2 || driftY > n*plain.ScaleY/2 {
+		t.Errorf("got drift (%g, %g), want within N*scale/2 = (%g, %g)",
+			driftX, driftY, n*plain.ScaleX/2, n*plain.ScaleY/2)
+	}
+	// Each delta alone is within half a step, so anything beyond a few
+	// steps has accumulated.
+	if driftX < 10*plain.ScaleX && driftY < 10*plain.ScaleY {
+		t.Errorf("got drift (%g, %g), expected it to accumulate past a few scale steps (%g, %g)",
+			driftX, driftY, plain.ScaleX, plain.ScaleY)
+	}
+
+	const interval = 256
+	defer func(k int) { KeyframeInterval = k }(KeyframeInterval)
+	KeyframeInterval = interval
+	keyed, err := CompressWithDelta(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "spiral.delta.gz")
+	if err := SaveDeltaCompressed(keyed, filename); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadDeltaCompressed(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = loaded.Decompress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyX, keyY := axisErrors(decoded)
+	t.Logf("max error without keyframes (%g, %g), with one every %d points (%g, %g)", driftX, driftY, interval, keyX, keyY)
+	if keyX > interval*keyed.ScaleX/2 || keyY > interval*keyed.ScaleY/2 {
+		t.Errorf("got error (%g, %g) with keyframes, want within interval*scale/2 = (%g, %g)",
+			keyX, keyY, interval*keyed.ScaleX/2, interval*keyed.ScaleY/2)
+	}
+	if info, err := os.Stat(filename); err != nil || info.Size() > EstimateDeltaSize(len(points)) {
+		t.Errorf("delta file with keyframes over the estimate %d (stat error %v)", EstimateDeltaSize(len(points)), err)
+	}
+
+	corrupt := *loaded
+	corrupt.Keyframes = corrupt.Keyframes[:len(corrupt.Keyframes)-2]
+	if _, err := corrupt.Decompress(); err == nil {
+		t.Error("expected an error for a missing keyframe")
+	}
+}
+
+// Test that with keyframes the reconstruction error stays within
+// K*scale/2 per axis however long the chain grows, while without them it
+// keeps growing. The chain circles with a constant step, so the scale is
+// the same at every length.
+func TestDelta_KeyframesBoundErrorAtAnyLength(t *testing.T) {
+	const interval = 1024
+	defer func(k int) { KeyframeInterval = k }(KeyframeInterval)
+
+	maxError := func(points []complex128) (errX, errY, scaleX, scaleY float64) {
+		compressed, err := CompressWithDelta(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := compressed.Decompress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range points {
+			errX = math.Max(errX, math.Abs(real(points[i]-decoded[i])))
+			errY = math.Max(errY, math.Abs(imag(points[i]-decoded[i])))
+		}
+		return errX, errY, compressed.ScaleX, compressed.ScaleY
+	}
+
+	var firstDrift, lastDrift float64
+	for _, n := range []int{1 << 12, 1 << 15, 1 << 18, 1 << 21} {
+		points := make([]complex128, n)
+		for i := range points {
+			points[i] = cmplx.Rect(1, 0.0123*float64(i))
+		}
+
+		KeyframeInterval = 0
+		driftX, driftY, _, _ := maxError(points)
+		KeyframeInterval = interval
+		errX, errY, scaleX, scaleY := maxError(points)
+		t.Logf("n=%d: max error (%g, %g) without keyframes, (%g, %g) with", n, driftX, driftY, errX, errY)
+
+		if errX > interval*scaleX/2 || errY > interval*scaleY/2 {
+			t.Errorf("n=%d: got error (%g, %g) with keyframes, want within K*scale/2 = (%g, %g)",
+				n, errX, errY, interval*scaleX/2, interval*scaleY/2)
+		}
+		drift := math.Max(driftX, driftY)
+		if firstDrift == 0 {
+			firstDrift = drift
+		}
+		lastDrift = drift
+	}
+	// Confirm the chain is long enough for drift to matter at all.
+	if lastDrift < 4*firstDrift {
+		t.Errorf("drift without keyframes grew only from %g to %g; the test chain no longer shows it", firstDrift, lastDrift)
+	}
+}
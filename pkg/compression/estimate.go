@@ -0,0 +1,41 @@
+package compression
+
+// gzipBound returns an upper bound on the gzip-compressed size of n bytes.
+// Incompressible input falls back to stored deflate blocks, which add 5
+// bytes per block of at most 64 KiB (pgzip also ends each of its blocks
+// with a 5-byte flush), plus the gzip header and trailer.
+func gzipBound(n int64) int64 {
+	return n + 5*(n/16384+1) + 64
+}
+
+// EstimateDeltaSize returns an upper bound on the size of the file
+// SaveDeltaCompressed writes for numPoints points: a 36-byte header, two
+// int16 deltas per point after the first, the 16-byte correction and the
+// keyframe interval, and 16 bytes per keyframe at the current
+// KeyframeInterval, gzip-compressed.
+func EstimateDeltaSize(numPoints int) int64 {
+	raw := int64(4*8 + 4 + 2*8 + 4)
+	if numPoints > 1 {
+		raw += 4 * int64(numPoints-1)
+		if KeyframeInterval > 0 {
+			raw += 16 * int64((numPoints-1)/KeyframeInterval)
+		}
+	}
+	return gzipBound(raw)
+}
+
+// EstimateMsgPackSize returns an upper bound on the size of the file
+// SaveMsgPack writes for numPoints points: under 128 bytes of bounds and
+// scale metadata, and two int16 values per point at up to 3 bytes each,
+// gzip-compressed.
+func EstimateMsgPackSize(numPoints int) int64 {
+	return gzipBound(128 + 6*int64(numPoints))
+}
+
+// EstimateVarintSize returns an upper bound on the size of the file
+// SaveVarint writes for numPoints points: a 40-byte header, and two grid
+// deltas per point of at most 4 varint bytes each (a delta spans at most
+// the grid's 2^20 steps, 22 bits once zigzagged), gzip-compressed.
+func EstimateVarintSize(numPoints int) int64 {
+	return gzipBound(4*8 + 4 + 4 + 8*int64(numPoints))
+}
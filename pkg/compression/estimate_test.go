@@ -0,0 +1,74 @@
+package compression
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Test that the size estimates bound the files actually written, both for
+// a smooth spiral and for noise that gzip can't shrink.
+func TestEstimateSize_BoundsFiles(t *testing.T) {
+	const n = 20000
+	rng := rand.New(rand.NewSource(1))
+	spiral := make([]complex128, n)
+	noise := make([]complex128, n)
+	for i := range spiral {
+		spiral[i] = cmplx.Rect(float64(i)/n, float64(i)*0.01*2*math.Pi)
+		noise[i] = complex(rng.Float64(), rng.Float64())
+	}
+
+	dir := t.TempDir()
+	for name, points := range map[string][]complex128{"spiral": spiral, "noise": noise} {
+		deltaFile := filepath.Join(dir, name+".delta")
+		delta, err := CompressWithDelta(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveDeltaCompressed(delta, deltaFile); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(deltaFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > EstimateDeltaSize(n) {
+			t.Errorf("%s: delta file is %d bytes, over the estimate %d", name, info.Size(), EstimateDeltaSize(n))
+		}
+
+		msgpackFile := filepath.Join(dir, name+".msgpack")
+		packed, err := CompressWithMsgPack(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveMsgPack(packed, msgpackFile); err != nil {
+			t.Fatal(err)
+		}
+		info, err = os.Stat(msgpackFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > EstimateMsgPackSize(n) {
+			t.Errorf("%s: msgpack file is %d bytes, over the estimate %d", name, info.Size(), EstimateMsgPackSize(n))
+		}
+
+		varintFile := filepath.Join(dir, name+".varint")
+		varint, err := CompressWithVarint(points)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveVarint(varint, varintFile); err != nil {
+			t.Fatal(err)
+		}
+		info, err = os.Stat(varintFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > EstimateVarintSize(n) {
+			t.Errorf("%s: varint file is %d bytes, over the estimate %d", name, info.Size(), EstimateVarintSize(n))
+		}
+	}
+}
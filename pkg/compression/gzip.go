@@ -0,0 +1,57 @@
+package compression
+
+import (
+	"compress/gzip"
+	"io"
+	"log"
+
+	"github.com/klauspost/pgzip"
+)
+
+// ParallelGzip makes the Save functions compress with pgzip, which splits the
+// stream into blocks and compresses them on all cores. The output is still a
+// standard gzip stream, so the existing loaders read it unchanged.
+var ParallelGzip = false
+
+// GzipLevel is the compression level the Save functions use, from
+// gzip.HuffmanOnly through gzip.BestCompression.
+var GzipLevel = gzip.DefaultCompression
+
+// newGzipWriter returns the gzip writer selected by ParallelGzip at
+// GzipLevel.
+func newGzipWriter(w io.Writer) (io.WriteCloser, error) {
+	if ParallelGzip {
+		return pgzip.NewWriterLevel(w, GzipLevel)
+	}
+	return gzip.NewWriterLevel(w, GzipLevel)
+}
+
+// saveGzipped writes filename as the gzip-compressed output of write. The
+// file is written atomically (see WriteFileAtomic), so an error from write,
+// the gzip flush or the file's own close leaves no partial file behind.
+func saveGzipped(filename string, write func(w io.Writer) error) error {
+	return WriteFileAtomic(filename, func(w io.Writer) error {
+		return writeGzipped(w, write)
+	})
+}
+
+// writeGzipped gzips the output of write into w. The gzip writer is closed
+// exactly once, even when write fails, and its Close error (which carries
+// the final flush to w) is returned unless an earlier write error takes
+// precedence.
+func writeGzipped(w io.Writer, write func(w io.Writer) error) error {
+	gzw, err := newGzipWriter(w)
+	if err != nil {
+		log.Printf("Error creating gzip writer: %v", err)
+		return err
+	}
+
+	err = write(gzw)
+	if cerr := gzw.Close(); cerr != nil {
+		log.Printf("Error closing gzip writer: %v", cerr)
+		if err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
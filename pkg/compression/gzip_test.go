@@ -0,0 +1,140 @@
+package compression
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that files written with parallel gzip load with the standard loaders.
+func TestParallelGzip_RoundTrip(t *testing.T) {
+	defer func(orig bool) { ParallelGzip = orig }(ParallelGzip)
+	ParallelGzip = true
+
+	points := testutil.SpiralLinks(50_000)
+	dir := t.TempDir()
+
+	delta, err := CompressWithDelta(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltaFile := filepath.Join(dir, "spiral.delta")
+	if err := SaveDeltaCompressed(delta, deltaFile); err != nil {
+		t.Fatal(err)
+	}
+	loadedDelta, err := LoadDeltaCompressed(deltaFile)
+	if err != nil {
+		t.Fatalf("standard loader failed on parallel gzip output: %v", err)
+	}
+	if loadedDelta.NumPoints != delta.NumPoints || len(loadedDelta.Deltas) != len(delta.Deltas) {
+		t.Fatalf("got %d points / %d deltas, want %d / %d",
+			loadedDelta.NumPoints, len(loadedDelta.Deltas), delta.NumPoints, len(delta.Deltas))
+	}
+	for i := range delta.Deltas {
+		if loadedDelta.Deltas[i] != delta.Deltas[i] {
+			t.Fatalf("delta %d: got %d, want %d", i, loadedDelta.Deltas[i], delta.Deltas[i])
+		}
+	}
+
+	packed, err := CompressWithMsgPack(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgpackFile := filepath.Join(dir, "spiral.msgpack")
+	if err := SaveMsgPack(packed, msgpackFile); err != nil {
+		t.Fatal(err)
+	}
+	loadedPacked, err := LoadMsgPack(msgpackFile)
+	if err != nil {
+		t.Fatalf("standard loader failed on parallel gzip output: %v", err)
+	}
+	if len(loadedPacked.Points) != len(packed.Points) {
+		t.Fatalf("got %d quantized values, want %d", len(loadedPacked.Points), len(packed.Points))
+	}
+	for i := range packed.Points {
+		if loadedPacked.Points[i] != packed.Points[i] {
+			t.Fatalf("value %d: got %d, want %d", i, loadedPacked.Points[i], packed.Points[i])
+		}
+	}
+}
+
+// flushFailWriter accepts the gzip header written on the first Write, then
+// fails, so the error only appears when the gzip writer flushes on Close.
+type flushFailWriter struct {
+	writes int
+	err    error
+}
+
+func (w *flushFailWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > 1 {
+		return 0, w.err
+	}
+	return len(p), nil
+}
+
+// Test that an error flushing the gzip stream on close is returned rather
+// than swallowed by a deferred Close.
+func TestWriteGzipped_CloseError(t *testing.T) {
+	errFlush := errors.New("flush failed")
+	w := &flushFailWriter{err: errFlush}
+	data := []byte("small payload that gzip buffers until close")
+
+	var n int
+	err := writeGzipped(w, func(w io.Writer) error {
+		var err error
+		n, err = w.Write(data)
+		return err
+	})
+	if n != len(data) {
+		t.Fatalf("got %d bytes accepted before close, want %d", n, len(data))
+	}
+	if !errors.Is(err, errFlush) {
+		t.Fatalf("got error %v, want %v", err, errFlush)
+	}
+}
+
+// Test that an error inside the callback, after some output has been
+// written, leaves no file at all, and that a successful save reads back.
+func TestSaveGzipped(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "spiral.gz")
+
+	errBoom := errors.New("boom")
+	err := saveGzipped(target, func(w io.Writer) error {
+		if _, err := w.Write(make([]byte, 1<<20)); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("got error %v, want %v", err, errBoom)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Fatalf("got %d files left behind (read error %v), want none", len(entries), err)
+	}
+
+	if err := saveGzipped(target, func(w io.Writer) error {
+		_, err := w.Write([]byte("payload"))
+		return err
+	}); err != nil {
+		t.Fatalf("saveGzipped: %v", err)
+	}
+	f, err := os.Open(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(gzr); err != nil || string(got) != "payload" {
+		t.Errorf("got %q (error %v), want %q", got, err, "payload")
+	}
+}
@@ -0,0 +1,93 @@
+package compression
+
+import (
+	"io"
+	"log"
+	"math"
+	"os"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// SpiralHeader is the metadata of a saved spiral, stored apart from the
+// point data so a frontend can set up its viewport before downloading the
+// points.
+type SpiralHeader struct {
+	// S is the point the spiral was computed at.
+	S struct {
+		Real float64 `msgpack:"re"`
+		Imag float64 `msgpack:"im"`
+	} `msgpack:"s"`
+
+	// N is the number of terms summed, or 0 when the points were loaded
+	// rather than computed and N is unknown.
+	N int64 `msgpack:"n"`
+
+	// NumPoints is the number of points in the points file.
+	NumPoints int `msgpack:"numPoints"`
+
+	// Bounds of the points, in full precision.
+	Bounds struct {
+		MinX float64 `msgpack:"minX"`
+		MaxX float64 `msgpack:"maxX"`
+		MinY float64 `msgpack:"minY"`
+		MaxY float64 `msgpack:"maxY"`
+	} `msgpack:"bounds"`
+}
+
+// NewSpiralHeader returns the header for points computed at s with N terms.
+func NewSpiralHeader(s complex128, N int, points []complex128) *SpiralHeader {
+	h := &SpiralHeader{N: int64(N), NumPoints: len(points)}
+	h.S.Real, h.S.Imag = real(s), imag(s)
+	if len(points) == 0 {
+		return h
+	}
+
+	h.Bounds.MinX, h.Bounds.MaxX = real(points[0]), real(points[0])
+	h.Bounds.MinY, h.Bounds.MaxY = imag(points[0]), imag(points[0])
+	for _, p := range points {
+		h.Bounds.MinX = math.Min(h.Bounds.MinX, real(p))
+		h.Bounds.MaxX = math.Max(h.Bounds.MaxX, real(p))
+		h.Bounds.MinY = math.Min(h.Bounds.MinY, imag(p))
+		h.Bounds.MaxY = math.Max(h.Bounds.MaxY, imag(p))
+	}
+	return h
+}
+
+// SaveSpiralHeader writes the header as plain MessagePack. It is a few dozen
+// bytes, so unlike the points it is not gzipped.
+func SaveSpiralHeader(h *SpiralHeader, filename string) error {
+	data, err := msgpack.Marshal(h)
+	if err != nil {
+		log.Printf("Error marshaling header: %v", err)
+		return err
+	}
+	return WriteFileAtomic(filename, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// LoadSpiralHeader reads a header written by SaveSpiralHeader.
+func LoadSpiralHeader(filename string) (*SpiralHeader, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var h SpiralHeader
+	if err := msgpack.Unmarshal(data, &h); err != nil {
+		log.Printf("Error unmarshaling header: %v", err)
+		return nil, err
+	}
+	return &h, nil
+}
+
+// SaveSpiralPoints writes the points alone in the MessagePack format read
+// by LoadMsgPack, for pairing with a header from SaveSpiralHeader.
+func SaveSpiralPoints(points []complex128, filename string) error {
+	compressed, err := CompressWithMsgPack(points)
+	if err != nil {
+		return err
+	}
+	return SaveMsgPack(compressed, filename)
+}
@@ -0,0 +1,53 @@
+package compression
+
+import (
+	"path/filepath"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that the header round-trips on its own and that its bounds match
+// those the points file computes.
+func TestSpiralHeader_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	headerFile := filepath.Join(dir, "spiral.header")
+	pointsFile := filepath.Join(dir, "spiral.msgpack.gz")
+
+	points := testutil.SpiralLinks(5000)
+	s := complex(0.5, 14.134725)
+	want := NewSpiralHeader(s, 100, points)
+
+	if err := SaveSpiralHeader(want, headerFile); err != nil {
+		t.Fatalf("SaveSpiralHeader: %v", err)
+	}
+	if err := SaveSpiralPoints(points, pointsFile); err != nil {
+		t.Fatalf("SaveSpiralPoints: %v", err)
+	}
+
+	got, err := LoadSpiralHeader(headerFile)
+	if err != nil {
+		t.Fatalf("LoadSpiralHeader: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("got header %+v, want %+v", *got, *want)
+	}
+	if complex(got.S.Real, got.S.Imag) != s || got.N != 100 || got.NumPoints != len(points) {
+		t.Errorf("got s %v, N %d, %d points; want %v, 100, %d",
+			complex(got.S.Real, got.S.Imag), got.N, got.NumPoints, s, len(points))
+	}
+
+	compressed, err := LoadMsgPack(pointsFile)
+	if err != nil {
+		t.Fatalf("LoadMsgPack: %v", err)
+	}
+	if n := len(compressed.Points) / 2; n != got.NumPoints {
+		t.Errorf("points file holds %d points, header says %d", n, got.NumPoints)
+	}
+	// The points file keeps its bounds in float32.
+	b := compressed.Bounds
+	if b.MinX != float32(got.Bounds.MinX) || b.MaxX != float32(got.Bounds.MaxX) ||
+		b.MinY != float32(got.Bounds.MinY) || b.MaxY != float32(got.Bounds.MaxY) {
+		t.Errorf("points file bounds %+v don't match header bounds %+v", b, got.Bounds)
+	}
+}
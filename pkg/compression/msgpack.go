@@ -2,7 +2,9 @@ package compression
 
 import (
 	"compress/gzip"
+	"io"
 	"log"
+	"math"
 	"os"
 
 	"github.com/vmihailenco/msgpack/v5"
@@ -66,17 +68,24 @@ func CompressWithMsgPack(points []complex128) (*MsgPackSpiral, error) {
 	}
 
 	// Second pass: quantize points
+	clamped := 0
 	for i, p := range points {
 		x := float32(real(p))
 		y := float32(imag(p))
 
 		// Quantize to int16
-		qx := int16((x - compressed.Bounds.MinX) / compressed.Scale.X)
-		qy := int16((y - compressed.Bounds.MinY) / compressed.Scale.Y)
+		qx, okX := quantizeInt16((x - compressed.Bounds.MinX) / compressed.Scale.X)
+		qy, okY := quantizeInt16((y - compressed.Bounds.MinY) / compressed.Scale.Y)
+		if !okX || !okY {
+			clamped++
+		}
 
 		compressed.Points[i*2] = qx
 		compressed.Points[i*2+1] = qy
 	}
+	if clamped > 0 {
+		log.Printf("Warning: clamped %d of %d points to the int16 range while quantizing", clamped, len(points))
+	}
 
 	// Test marshal to ensure it works
 	data, err := msgpack.Marshal(compressed)
@@ -103,24 +112,16 @@ func SaveMsgPack(compressed *MsgPackSpiral, filename string) error {
 	log.Printf("MessagePack encoded size: %d bytes", len(data))
 
 	// Save with gzip compression
-	file, err := os.Create(filename)
-	if err != nil {
-		log.Printf("Error creating file: %v", err)
+	var n int
+	err = saveGzipped(filename, func(w io.Writer) error {
+		var err error
+		n, err = w.Write(data)
+		if err != nil {
+			log.Printf("Error writing compressed data: %v", err)
+		}
 		return err
-	}
-	defer file.Close()
-
-	gzw := gzip.NewWriter(file)
-	defer gzw.Close()
-
-	n, err := gzw.Write(data)
+	})
 	if err != nil {
-		log.Printf("Error writing compressed data: %v", err)
-		return err
-	}
-
-	if err := gzw.Close(); err != nil {
-		log.Printf("Error closing gzip writer: %v", err)
 		return err
 	}
 
@@ -152,16 +153,17 @@ func LoadMsgPack(filename string) (*MsgPackSpiral, error) {
 	totalRead := 0
 
 	for {
+		// Keep the bytes from the final read, which may arrive alongside EOF.
 		n, err := gzr.Read(buf)
+		data = append(data, buf[:n]...)
+		totalRead += n
 		if err != nil {
-			if err.Error() == "EOF" {
+			if err == io.EOF {
 				break
 			}
 			log.Printf("Error reading data: %v", err)
 			return nil, err
 		}
-		data = append(data, buf[:n]...)
-		totalRead += n
 	}
 
 	log.Printf("Read %d bytes of compressed data", totalRead)
@@ -190,6 +192,22 @@ func (c *MsgPackSpiral) Decompress() []complex128 {
 	return points
 }
 
+// quantizeInt16 truncates v toward zero like an int16 conversion, but
+// clamps it to [math.MinInt16, math.MaxInt16] first, since converting an
+// out-of-range float is implementation-defined in Go. It reports false when
+// v had to be clamped; NaN clamps to 0.
+func quantizeInt16(v float32) (int16, bool) {
+	switch {
+	case v != v:
+		return 0, false
+	case v >= math.MaxInt16+1:
+		return math.MaxInt16, false
+	case v <= math.MinInt16-1:
+		return math.MinInt16, false
+	}
+	return int16(v), true
+}
+
 func min32(a, b float32) float32 {
 	if a < b {
 		return a
@@ -0,0 +1,55 @@
+package compression
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// Test that points on the exact bounds quantize inside the int16 range and
+// reconstruct to within a quantization step, and that out-of-range values
+// clamp instead of wrapping.
+func TestCompressWithMsgPack_BoundsQuantization(t *testing.T) {
+	// Bounds that float32 can't represent exactly, so the ends carry
+	// rounding error into the scaled values.
+	points := []complex128{
+		complex(-1.1, 0.3), complex(7.7, 0.3), complex(-1.1, 9.9),
+		complex(7.7, 9.9), complex(3.3, 5.1),
+	}
+	compressed, err := CompressWithMsgPack(points)
+	if err != nil {
+		t.Fatalf("CompressWithMsgPack: %v", err)
+	}
+	for i, q := range compressed.Points {
+		if q < 0 || q > 29001 {
+			t.Errorf("quantized value %d is %d, want within [0, 29001]", i, q)
+		}
+	}
+
+	got := compressed.Decompress()
+	step := math.Max(float64(compressed.Scale.X), float64(compressed.Scale.Y))
+	for i, p := range points {
+		if d := cmplx.Abs(got[i] - p); d > 2*step {
+			t.Errorf("point %d: got %v, want %v (error %g above two steps of %g)", i, got[i], p, d, step)
+		}
+	}
+
+	testCases := []struct {
+		v    float32
+		want int16
+		ok   bool
+	}{
+		{29000.7, 29000, true},
+		{32767.9, 32767, true},
+		{32768, 32767, false},
+		{1e9, 32767, false},
+		{-32768.5, -32768, true},
+		{-40000, -32768, false},
+		{float32(math.NaN()), 0, false},
+	}
+	for _, tc := range testCases {
+		if got, ok := quantizeInt16(tc.v); got != tc.want || ok != tc.ok {
+			t.Errorf("quantizeInt16(%v): got (%d, %v), want (%d, %v)", tc.v, got, ok, tc.want, tc.ok)
+		}
+	}
+}
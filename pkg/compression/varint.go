@@ -0,0 +1,219 @@
+package compression
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+)
+
+// varintGridSteps is the number of grid steps spanning each axis of the
+// bounding box. Points are snapped to the grid, so the reconstruction error
+// is at most half a step per axis and, unlike the int16 deltas, does not
+// accumulate along the chain.
+const varintGridSteps = 1 << 20
+
+// VarintCompressed represents a spiral snapped to a grid over its bounding
+// box, with the steps between consecutive grid points stored as zigzag
+// varints. Densely sampled chains move only a few grid steps per point, so
+// most deltas take one byte instead of an int16's two.
+type VarintCompressed struct {
+	// Grid origin: the bottom-left corner of the bounding box
+	MinX, MinY float64
+	// Size of one grid step on each axis
+	ScaleX, ScaleY float64
+	// Number of points
+	NumPoints uint32
+	// Zigzag varint grid deltas, alternating x and y; the first pair is
+	// the first point's offset from the origin
+	Deltas []byte
+}
+
+// zigzag maps signed integers to unsigned ones so that values near zero,
+// of either sign, get short varints.
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// unzigzag inverts zigzag.
+func unzigzag(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// CompressWithVarint snaps points to a grid over their bounding box and
+// encodes the grid deltas as zigzag varints.
+func CompressWithVarint(points []complex128) (*VarintCompressed, error) {
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	log.Printf("Starting varint compression of %d points", len(points))
+
+	minX, maxX := real(points[0]), real(points[0])
+	minY, maxY := imag(points[0]), imag(points[0])
+	for _, p := range points[1:] {
+		minX = math.Min(minX, real(p))
+		maxX = math.Max(maxX, real(p))
+		minY = math.Min(minY, imag(p))
+		maxY = math.Max(maxY, imag(p))
+	}
+	if math.IsNaN(minX) || math.IsNaN(minY) || math.IsInf(maxX-minX, 0) || math.IsInf(maxY-minY, 0) {
+		return nil, fmt.Errorf("cannot varint-encode non-finite points")
+	}
+
+	compressed := &VarintCompressed{
+		MinX:      minX,
+		MinY:      minY,
+		ScaleX:    (maxX - minX) / varintGridSteps,
+		ScaleY:    (maxY - minY) / varintGridSteps,
+		NumPoints: uint32(len(points)),
+	}
+	if compressed.ScaleX == 0 {
+		compressed.ScaleX = 1.0
+	}
+	if compressed.ScaleY == 0 {
+		compressed.ScaleY = 1.0
+	}
+
+	log.Printf("Using grid steps - X: %g, Y: %g", compressed.ScaleX, compressed.ScaleY)
+
+	// Most deltas fit in a byte each; append grows the rest.
+	compressed.Deltas = make([]byte, 0, 2*len(points))
+	var prevX, prevY int64
+	for _, p := range points {
+		x := int64(math.Round((real(p) - minX) / compressed.ScaleX))
+		y := int64(math.Round((imag(p) - minY) / compressed.ScaleY))
+		compressed.Deltas = binary.AppendUvarint(compressed.Deltas, zigzag(x-prevX))
+		compressed.Deltas = binary.AppendUvarint(compressed.Deltas, zigzag(y-prevY))
+		prevX, prevY = x, y
+	}
+
+	log.Printf("Successfully compressed to %d bytes of varint deltas", len(compressed.Deltas))
+	return compressed, nil
+}
+
+// SaveVarint saves the compressed data to a file with gzip compression: the
+// grid origin and steps, the point count, the byte length of the deltas and
+// the deltas themselves.
+func SaveVarint(compressed *VarintCompressed, filename string) error {
+	log.Printf("Starting to save varint compressed data to %s", filename)
+
+	err := saveGzipped(filename, func(gzw io.Writer) error {
+		header := [4]float64{compressed.MinX, compressed.MinY, compressed.ScaleX, compressed.ScaleY}
+		if err := binary.Write(gzw, binary.LittleEndian, header); err != nil {
+			log.Printf("Error writing grid: %v", err)
+			return err
+		}
+		if err := binary.Write(gzw, binary.LittleEndian, compressed.NumPoints); err != nil {
+			log.Printf("Error writing NumPoints: %v", err)
+			return err
+		}
+		if err := binary.Write(gzw, binary.LittleEndian, uint32(len(compressed.Deltas))); err != nil {
+			log.Printf("Error writing Deltas length: %v", err)
+			return err
+		}
+		if _, err := gzw.Write(compressed.Deltas); err != nil {
+			log.Printf("Error writing Deltas: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Successfully saved varint compressed data")
+	return nil
+}
+
+// LoadVarint loads varint compressed data from a file.
+func LoadVarint(filename string) (*VarintCompressed, error) {
+	log.Printf("Starting to load varint compressed data from %s", filename)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		log.Printf("Error opening file: %v", err)
+		return nil, err
+	}
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		log.Printf("Error creating gzip reader: %v", err)
+		return nil, err
+	}
+	defer gzr.Close()
+
+	var header [4]float64
+	if err := binary.Read(gzr, binary.LittleEndian, &header); err != nil {
+		log.Printf("Error reading grid: %v", err)
+		return nil, err
+	}
+	compressed := &VarintCompressed{
+		MinX:   header[0],
+		MinY:   header[1],
+		ScaleX: header[2],
+		ScaleY: header[3],
+	}
+	if err := binary.Read(gzr, binary.LittleEndian, &compressed.NumPoints); err != nil {
+		log.Printf("Error reading NumPoints: %v", err)
+		return nil, err
+	}
+
+	var length uint32
+	if err := binary.Read(gzr, binary.LittleEndian, &length); err != nil {
+		log.Printf("Error reading Deltas length: %v", err)
+		return nil, err
+	}
+	// Each point takes at least two bytes, so a length that can't hold
+	// NumPoints is corrupt; checking before allocating also stops a bad
+	// header from requesting gigabytes.
+	if uint64(length) < 2*uint64(compressed.NumPoints) || uint64(length) > 2*binary.MaxVarintLen64*uint64(compressed.NumPoints) {
+		err := fmt.Errorf("varint data has %d bytes, impossible for %d points", length, compressed.NumPoints)
+		log.Printf("Error reading Deltas length: %v", err)
+		return nil, err
+	}
+	compressed.Deltas = make([]byte, length)
+	if _, err := io.ReadFull(gzr, compressed.Deltas); err != nil {
+		log.Printf("Error reading Deltas: %v", err)
+		return nil, err
+	}
+
+	log.Printf("Successfully loaded %d points", compressed.NumPoints)
+	return compressed, nil
+}
+
+// Decompress converts the compressed data back to points. It returns an
+// error if the deltas are malformed or hold a different number of points
+// than NumPoints.
+func (c *VarintCompressed) Decompress() ([]complex128, error) {
+	points := make([]complex128, 0, c.NumPoints)
+	data := c.Deltas
+	var x, y int64
+	for len(data) > 0 {
+		dx, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed varint at byte %d", len(c.Deltas)-len(data))
+		}
+		data = data[n:]
+		dy, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed varint at byte %d", len(c.Deltas)-len(data))
+		}
+		data = data[n:]
+
+		x += unzigzag(dx)
+		y += unzigzag(dy)
+		points = append(points, complex(
+			c.MinX+float64(x)*c.ScaleX,
+			c.MinY+float64(y)*c.ScaleY,
+		))
+	}
+	if len(points) != int(c.NumPoints) {
+		return nil, fmt.Errorf("varint data holds %d points, want %d", len(points), c.NumPoints)
+	}
+	return points, nil
+}
@@ -0,0 +1,106 @@
+package compression
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zeta-scale-go/internal/testutil"
+)
+
+// Test that a saved varint file reloads to within half a grid step of every
+// original point, and that corrupt data is reported rather than decoded.
+func TestVarint_RoundTrip(t *testing.T) {
+	points := testutil.SpiralLinks(10000)
+	points = append(points, complex(-3, 25), complex(1e3, -1e3), points[0])
+
+	compressed, err := CompressWithVarint(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "spiral.varint.gz")
+	if err := SaveVarint(compressed, filename); err != nil {
+		t.Fatalf("SaveVarint: %v", err)
+	}
+	loaded, err := LoadVarint(filename)
+	if err != nil {
+		t.Fatalf("LoadVarint: %v", err)
+	}
+	decoded, err := loaded.Decompress()
+	if err != nil {
+		t.Fatalf("Decompress: %v", err)
+	}
+	maxErr, err := ReconstructionError(points, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit := math.Hypot(compressed.ScaleX, compressed.ScaleY) / 2; maxErr > limit {
+		t.Errorf("reconstruction error %g, want at most half a grid step (%g)", maxErr, limit)
+	}
+
+	corrupt := *loaded
+	corrupt.NumPoints++
+	if _, err := corrupt.Decompress(); err == nil {
+		t.Error("expected an error for a point count the data doesn't hold")
+	}
+	corrupt = *loaded
+	corrupt.Deltas = append(corrupt.Deltas[:len(corrupt.Deltas):len(corrupt.Deltas)], 0x80)
+	if _, err := corrupt.Decompress(); err == nil {
+		t.Error("expected an error for a truncated varint")
+	}
+}
+
+// Test that on a densely sampled spiral, where each point moves only a few
+// grid steps, varint deltas are smaller than the int16 codec's both before
+// and after gzip, while every point stays within half a grid step.
+func TestVarint_SmallerThanInt16OnDenseSpiral(t *testing.T) {
+	points := testutil.SpiralLinks(1 << 20)
+	dir := t.TempDir()
+
+	varint, err := CompressWithVarint(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delta, err := CompressWithDelta(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, int16Bytes := len(varint.Deltas), 2*len(delta.Deltas); got >= int16Bytes {
+		t.Errorf("varint deltas take %d bytes, want fewer than the int16 deltas' %d", got, int16Bytes)
+	}
+
+	varintFile := filepath.Join(dir, "spiral.varint.gz")
+	if err := SaveVarint(varint, varintFile); err != nil {
+		t.Fatal(err)
+	}
+	deltaFile := filepath.Join(dir, "spiral.delta.gz")
+	if err := SaveDeltaCompressed(delta, deltaFile); err != nil {
+		t.Fatal(err)
+	}
+	varintInfo, err := os.Stat(varintFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltaInfo, err := os.Stat(deltaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("varint file %d bytes, int16 delta file %d bytes", varintInfo.Size(), deltaInfo.Size())
+	if varintInfo.Size() >= deltaInfo.Size() {
+		t.Errorf("varint file is %d bytes, want smaller than the int16 delta file's %d", varintInfo.Size(), deltaInfo.Size())
+	}
+
+	varintErr, err := VerifyVarintFile(varintFile, points)
+	if err != nil {
+		t.Fatalf("VerifyVarintFile: %v", err)
+	}
+	deltaErr, err := VerifyDeltaFile(deltaFile, points)
+	if err != nil {
+		t.Fatalf("VerifyDeltaFile: %v", err)
+	}
+	t.Logf("reconstruction error: varint %g, int16 delta %g", varintErr, deltaErr)
+	if limit := math.Hypot(varint.ScaleX, varint.ScaleY) / 2; varintErr > limit {
+		t.Errorf("varint reconstruction error %g, want at most half a grid step (%g)", varintErr, limit)
+	}
+}
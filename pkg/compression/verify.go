@@ -0,0 +1,60 @@
+package compression
+
+import (
+	"fmt"
+	"math/cmplx"
+)
+
+// ReconstructionError returns the largest distance between corresponding
+// points of original and reconstructed. It is an error for the two to differ
+// in length, since a dropped or extra point is not a precision loss.
+func ReconstructionError(original, reconstructed []complex128) (float64, error) {
+	if len(original) != len(reconstructed) {
+		return 0, fmt.Errorf("reconstructed %d points, want %d", len(reconstructed), len(original))
+	}
+	var maxErr float64
+	for i := range original {
+		if d := cmplx.Abs(original[i] - reconstructed[i]); d > maxErr {
+			maxErr = d
+		}
+	}
+	return maxErr, nil
+}
+
+// VerifyDeltaFile reloads a delta-compressed file and returns its
+// ReconstructionError against original.
+func VerifyDeltaFile(filename string, original []complex128) (float64, error) {
+	compressed, err := LoadDeltaCompressed(filename)
+	if err != nil {
+		return 0, err
+	}
+	points, err := compressed.Decompress()
+	if err != nil {
+		return 0, err
+	}
+	return ReconstructionError(original, points)
+}
+
+// VerifyMsgPackFile reloads a MessagePack file and returns its
+// ReconstructionError against original.
+func VerifyMsgPackFile(filename string, original []complex128) (float64, error) {
+	compressed, err := LoadMsgPack(filename)
+	if err != nil {
+		return 0, err
+	}
+	return ReconstructionError(original, compressed.Decompress())
+}
+
+// VerifyVarintFile reloads a varint-compressed file and returns its
+// ReconstructionError against original.
+func VerifyVarintFile(filename string, original []complex128) (float64, error) {
+	compressed, err := LoadVarint(filename)
+	if err != nil {
+		return 0, err
+	}
+	points, err := compressed.Decompress()
+	if err != nil {
+		return 0, err
+	}
+	return ReconstructionError(original, points)
+}
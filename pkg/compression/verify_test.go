@@ -0,0 +1,67 @@
+package compression
+
+import (
+	"math"
+	"math/cmplx"
+	"path/filepath"
+	"testing"
+)
+
+// Test that verifying a saved file reloads it and reports the same finite
+// error as comparing against the in-memory decompression.
+func TestVerifyFiles(t *testing.T) {
+	points := make([]complex128, 1000)
+	for i := range points {
+		u := float64(i) / float64(len(points))
+		points[i] = cmplx.Rect(1+u, 12*math.Pi*u)
+	}
+	dir := t.TempDir()
+
+	delta, err := CompressWithDelta(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltaFile := filepath.Join(dir, "spiral.delta")
+	if err := SaveDeltaCompressed(delta, deltaFile); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := delta.Decompress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ReconstructionError(points, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := VerifyDeltaFile(deltaFile, points)
+	if err != nil {
+		t.Fatalf("VerifyDeltaFile: %v", err)
+	}
+	if got != want || math.IsInf(got, 0) || math.IsNaN(got) {
+		t.Errorf("delta: got error %v, want finite %v", got, want)
+	}
+
+	packed, err := CompressWithMsgPack(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgpackFile := filepath.Join(dir, "spiral.msgpack")
+	if err := SaveMsgPack(packed, msgpackFile); err != nil {
+		t.Fatal(err)
+	}
+	want, err = ReconstructionError(points, packed.Decompress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = VerifyMsgPackFile(msgpackFile, points)
+	if err != nil {
+		t.Fatalf("VerifyMsgPackFile: %v", err)
+	}
+	if got != want || math.IsInf(got, 0) || math.IsNaN(got) {
+		t.Errorf("msgpack: got error %v, want finite %v", got, want)
+	}
+
+	if _, err := ReconstructionError(points, points[1:]); err == nil {
+		t.Error("expected an error for mismatched lengths")
+	}
+}
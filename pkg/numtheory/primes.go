@@ -0,0 +1,77 @@
+// Package numtheory provides number-theoretic helpers shared by the spiral
+// tools, such as prime generation for Euler products.
+package numtheory
+
+import "math"
+
+// segmentSize is the number of integers sieved per segment. It bounds the
+// working memory of Primes independently of the limit.
+const segmentSize = 1 << 15
+
+// Primes returns all primes strictly less than limit in increasing order.
+//
+// It uses a segmented sieve of Eratosthenes: the base primes up to
+// sqrt(limit) are sieved once, then [sqrt(limit), limit) is processed in
+// fixed-size windows, so only the result slice grows with limit.
+func Primes(limit int) []int {
+	if limit <= 2 {
+		return nil
+	}
+
+	root := int(math.Sqrt(float64(limit)))
+	for root*root < limit {
+		root++
+	}
+
+	// Simple sieve for the base primes below root.
+	composite := make([]bool, root+1)
+	var base []int
+	for i := 2; i <= root; i++ {
+		if composite[i] {
+			continue
+		}
+		base = append(base, i)
+		for j := i * i; j <= root; j += i {
+			composite[j] = true
+		}
+	}
+
+	primes := make([]int, 0, estimatePrimeCount(limit))
+	for _, p := range base {
+		if p < limit {
+			primes = append(primes, p)
+		}
+	}
+
+	segment := make([]bool, segmentSize)
+	for low := root + 1; low < limit; low += segmentSize {
+		high := min(low+segmentSize, limit)
+		window := segment[:high-low]
+		clear(window)
+
+		for _, p := range base {
+			// First multiple of p in [low, high), never below p*p.
+			start := max(p*p, (low+p-1)/p*p)
+			for j := start; j < high; j += p {
+				window[j-low] = true
+			}
+		}
+
+		for i, isComposite := range window {
+			if !isComposite {
+				primes = append(primes, low+i)
+			}
+		}
+	}
+
+	return primes
+}
+
+// estimatePrimeCount returns an upper bound on π(n) for preallocation,
+// using Rosser and Schoenfeld's n/ln(n) * 1.26 bound.
+func estimatePrimeCount(n int) int {
+	if n < 17 {
+		return 6
+	}
+	return int(1.26 * float64(n) / math.Log(float64(n)))
+}
@@ -0,0 +1,60 @@
+package numtheory
+
+import "testing"
+
+func TestPrimes_CountBelowMillion(t *testing.T) {
+	if got := len(Primes(1_000_000)); got != 78498 {
+		t.Errorf("got %d primes below 10^6, want 78498", got)
+	}
+}
+
+func TestPrimes_Small(t *testing.T) {
+	want := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}
+	got := Primes(30)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	for _, limit := range []int{-1, 0, 1, 2} {
+		if got := Primes(limit); len(got) != 0 {
+			t.Errorf("Primes(%d) = %v, want none", limit, got)
+		}
+	}
+	if got := Primes(3); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Primes(3) = %v, want [2]", got)
+	}
+}
+
+// Test that segment boundaries neither drop nor duplicate primes.
+func TestPrimes_AcrossSegments(t *testing.T) {
+	limit := 3*segmentSize + 17
+	primes := Primes(limit)
+	for i := 1; i < len(primes); i++ {
+		if primes[i] <= primes[i-1] {
+			t.Fatalf("primes not strictly increasing at %d: %d, %d", i, primes[i-1], primes[i])
+		}
+	}
+
+	isPrime := func(n int) bool {
+		for d := 2; d*d <= n; d++ {
+			if n%d == 0 {
+				return false
+			}
+		}
+		return n >= 2
+	}
+	count := 0
+	for n := 2; n < limit; n++ {
+		if isPrime(n) {
+			count++
+		}
+	}
+	if len(primes) != count {
+		t.Errorf("got %d primes below %d, want %d", len(primes), limit, count)
+	}
+}